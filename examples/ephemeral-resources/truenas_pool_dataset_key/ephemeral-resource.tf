@@ -0,0 +1,17 @@
+resource "truenas_pool_dataset" "backups" {
+  name                    = "tank/backups"
+  encryption              = true
+  encryption_generate_key = true
+}
+
+# Escrow the generated key without it ever touching the state
+ephemeral "truenas_pool_dataset_key" "backups" {
+  dataset = truenas_pool_dataset.backups.name
+}
+
+resource "vault_kv_secret_v2" "backups_key" {
+  mount                = "secret"
+  name                 = "truenas/tank/backups"
+  data_json_wo         = jsonencode({ key = ephemeral.truenas_pool_dataset_key.backups.key })
+  data_json_wo_version = 1
+}
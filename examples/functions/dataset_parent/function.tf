@@ -0,0 +1,3 @@
+output "parent" {
+  value = provider::truenas::dataset_parent(truenas_pool_dataset.db.name) # "tank/apps"
+}
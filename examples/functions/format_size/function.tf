@@ -0,0 +1,3 @@
+output "quota" {
+  value = provider::truenas::format_size(53687091200) # "50GiB"
+}
@@ -0,0 +1,3 @@
+output "target_iqn" {
+  value = provider::truenas::iqn(truenas_iscsi_global.main.basename, truenas_iscsi_target.lun0.name)
+}
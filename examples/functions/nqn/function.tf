@@ -0,0 +1,3 @@
+output "subsystem_nqn" {
+  value = provider::truenas::nqn(truenas_nvmet_global.main.basenqn, "vm-disks")
+}
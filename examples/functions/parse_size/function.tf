@@ -0,0 +1,3 @@
+output "quota_bytes" {
+  value = provider::truenas::parse_size("1.5TiB") # 1649267441664
+}
@@ -0,0 +1,5 @@
+resource "truenas_cronjob" "backup" {
+  command  = "/usr/local/bin/backup.sh"
+  user     = "root"
+  schedule = provider::truenas::validate_cron("30 2 * * mon-fri")
+}
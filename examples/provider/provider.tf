@@ -12,6 +12,12 @@ provider "truenas" {
   insecure = true
 }
 
+# Connection settings loaded from the "backup" profile of ~/.config/truenas/config
+provider "truenas" {
+  alias   = "backup"
+  profile = "backup"
+}
+
 variable "truenas_api_key" {
   type      = string
   sensitive = true
@@ -1 +1,3 @@
+# Import by ID or by key name
 terraform import truenas_api_key.example 42
+terraform import truenas_api_key.example terraform
@@ -1 +1,3 @@
+# Import by ID or by user and command
 terraform import truenas_cronjob.example 1
+terraform import truenas_cronjob.example "root:/usr/local/bin/backup.sh"
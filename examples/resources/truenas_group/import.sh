@@ -1 +1,3 @@
+# Import by ID or by group name
 terraform import truenas_group.example 42
+terraform import truenas_group.example developers
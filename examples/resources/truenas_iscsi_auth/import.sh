@@ -1 +1,3 @@
+# Import by ID or by group tag and user, as tag:user
 terraform import truenas_iscsi_auth.example 1
+terraform import truenas_iscsi_auth.example 1:chapuser
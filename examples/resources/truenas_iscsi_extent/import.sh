@@ -1 +1,3 @@
+# Import by ID or by extent name
 terraform import truenas_iscsi_extent.example 1
+terraform import truenas_iscsi_extent.example extent1
@@ -1 +1,3 @@
+# Import by ID or by comment
 terraform import truenas_iscsi_initiator.example 1
+terraform import truenas_iscsi_initiator.example "VMware hosts"
@@ -1 +1,3 @@
+# Import by ID or by listen address (optionally with the global iSCSI port)
 terraform import truenas_iscsi_portal.example 1
+terraform import truenas_iscsi_portal.example 10.0.0.10:3260
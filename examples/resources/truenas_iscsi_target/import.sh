@@ -1 +1,4 @@
+# Import by ID, target name or full IQN
 terraform import truenas_iscsi_target.example 1
+terraform import truenas_iscsi_target.example target1
+terraform import truenas_iscsi_target.example iqn.2005-10.org.freenas.ctl:target1
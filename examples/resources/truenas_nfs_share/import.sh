@@ -1 +1,3 @@
+# Import by ID or by exported path
 terraform import truenas_nfs_share.example 1
+terraform import truenas_nfs_share.example /mnt/tank/media
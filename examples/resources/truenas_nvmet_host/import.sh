@@ -0,0 +1,3 @@
+# Import by ID or by host NQN
+terraform import truenas_nvmet_host.example 1
+terraform import truenas_nvmet_host.example nqn.2014-08.org.nvmexpress:uuid:3f6e8f2a-1b7c-4d5e-9a0b-2c3d4e5f6a7b
@@ -0,0 +1,3 @@
+# Import by ID or by device path
+terraform import truenas_nvmet_namespace.example 1
+terraform import truenas_nvmet_namespace.example zvol/tank/vols/ns1
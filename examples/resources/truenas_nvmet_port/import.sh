@@ -0,0 +1,3 @@
+# Import by ID or by listen address, with or without the port
+terraform import truenas_nvmet_port.example 1
+terraform import truenas_nvmet_port.example 10.0.0.10:4420
@@ -0,0 +1,3 @@
+# Import by ID, subsystem name or subsystem NQN
+terraform import truenas_nvmet_subsys.example 1
+terraform import truenas_nvmet_subsys.example nqn.2011-06.com.truenas:uuid:68bf9433-63ef-49f5-a921-4c0f8190fd94:subsys1
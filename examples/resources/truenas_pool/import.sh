@@ -0,0 +1 @@
+terraform import truenas_pool.tank tank
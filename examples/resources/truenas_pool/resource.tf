@@ -0,0 +1,17 @@
+# Two mirrored data vdevs, an NVMe log device and a hot spare, referenced by serial number.
+resource "truenas_pool" "tank" {
+  name = "tank"
+
+  topology = {
+    data = [
+      { type = "MIRROR", disks = ["ZL2ABC01", "ZL2ABC02"] },
+      { type = "MIRROR", disks = ["ZL2ABC03", "ZL2ABC04"] },
+    ]
+    log    = [{ type = "STRIPE", disks = ["{uuid}6c1f2e4a-0b7d-4f3e-9a51-3d2b8c7e1f00"] }]
+    spares = ["ZL2ABC05"]
+  }
+
+  encryption    = true
+  checksum      = "SHA256"
+  deduplication = "OFF"
+}
@@ -8,7 +8,7 @@ resource "truenas_pool_dataset" "media" {
   name        = "tank/media"
   compression = "LZ4"
   atime       = "OFF"
-  quota       = 1099511627776 # 1 TiB
+  quota       = "1TiB"
   comments    = "Media storage managed by Terraform"
 }
 
@@ -19,3 +19,72 @@ resource "truenas_pool_dataset" "nested" {
   recordsize       = "128K"
   sync             = "ALWAYS"
 }
+
+# SMB share dataset; the preset chooses acltype, aclmode and casesensitivity
+resource "truenas_pool_dataset" "office" {
+  name       = "tank/office"
+  share_type = "SMB"
+  xattr      = "SA"
+
+  # Keep small files on the special vdev
+  special_small_block_size = "64K"
+}
+
+# Custom ZFS user properties read by backup tooling
+resource "truenas_pool_dataset" "projects" {
+  name = "tank/projects"
+
+  user_properties = {
+    "com.example:backup"    = "daily"
+    "com.example:retention" = "30d"
+  }
+}
+
+# Large datasets can take a long time to destroy
+resource "truenas_pool_dataset" "archive" {
+  name = "tank/archive"
+
+  timeouts = {
+    delete = "1h"
+  }
+}
+
+# Deletion protection is enabled by default; disable it for disposable datasets
+resource "truenas_pool_dataset" "scratch" {
+  name                = "tank/scratch"
+  deletion_protection = false
+}
+
+# Test environment cloned from a golden dataset
+resource "truenas_pool_dataset" "test_env" {
+  name                = "tank/envs/test"
+  clone_from_snapshot = "tank/golden@base"
+  compression         = "ZSTD"
+  deletion_protection = false
+}
+
+# Encrypted tenant dataset; the passphrase is write-only and never stored in state
+variable "acme_passphrase" {
+  type      = string
+  sensitive = true
+}
+
+resource "truenas_pool_dataset" "tenant" {
+  name                     = "tank/tenants/acme"
+  encryption               = true
+  encryption_passphrase_wo = var.acme_passphrase
+  locked                   = false
+}
+
+variable "globex_passphrase" {
+  type      = string
+  sensitive = true
+}
+
+# Rotate the key by changing the passphrase and bumping encryption_wo_version
+resource "truenas_pool_dataset" "tenant_rotated" {
+  name                     = "tank/tenants/globex"
+  encryption               = true
+  encryption_passphrase_wo = var.globex_passphrase
+  encryption_wo_version    = 2
+}
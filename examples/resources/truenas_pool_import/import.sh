@@ -0,0 +1 @@
+terraform import truenas_pool_import.archive archive
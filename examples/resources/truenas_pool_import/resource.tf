@@ -0,0 +1,12 @@
+# Adopt the pool whose disks were moved from the old chassis. Destroying the resource
+# exports the pool again, so the disks can be pulled.
+resource "truenas_pool_import" "archive" {
+  guid = "14823316547305716235"
+
+  cascade          = false
+  restart_services = true
+
+  timeouts = {
+    create = "30m"
+  }
+}
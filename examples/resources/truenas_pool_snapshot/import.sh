@@ -0,0 +1 @@
+terraform import truenas_pool_snapshot.pre_migration tank/apps@pre-migration
@@ -0,0 +1,13 @@
+# Snapshot the application datasets before a migration and keep it until the
+# resource is removed.
+resource "truenas_pool_snapshot" "pre_migration" {
+  dataset   = "tank/apps"
+  name      = "pre-migration"
+  recursive = true
+  exclude   = ["tank/apps/cache"]
+  hold      = true
+
+  properties = {
+    "org.example:reason" = "postgres 16 upgrade"
+  }
+}
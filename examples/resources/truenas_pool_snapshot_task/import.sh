@@ -1 +1,3 @@
+# Import by ID or by dataset
 terraform import truenas_pool_snapshot_task.example 1
+terraform import truenas_pool_snapshot_task.example tank/data
@@ -0,0 +1 @@
+terraform import truenas_pool_zvol.lun0 tank/vols/lun0
@@ -0,0 +1,13 @@
+resource "truenas_pool_zvol" "lun0" {
+  name         = "tank/vols/lun0"
+  volsize      = "100GiB"
+  volblocksize = "16K"
+  sparse       = true
+  compression  = "LZ4"
+}
+
+resource "truenas_iscsi_extent" "lun0" {
+  name = "lun0"
+  type = "DISK"
+  disk = truenas_pool_zvol.lun0.device_path
+}
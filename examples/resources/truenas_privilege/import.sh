@@ -1 +1,3 @@
+# Import by ID or by privilege name
 terraform import truenas_privilege.example 1
+terraform import truenas_privilege.example operators
@@ -3,3 +3,15 @@ resource "truenas_service" "ssh" {
   enable  = true
   running = true
 }
+
+# Services that take a while to start can be given a longer deadline
+resource "truenas_service" "nfs" {
+  service = "nfs"
+  enable  = true
+  running = true
+
+  timeouts = {
+    create = "5m"
+    update = "5m"
+  }
+}
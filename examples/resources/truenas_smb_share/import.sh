@@ -1 +1,3 @@
+# Import by ID or by share name
 terraform import truenas_smb_share.example 1
+terraform import truenas_smb_share.example media
@@ -1 +1,3 @@
+# Import by ID or by username
 terraform import truenas_user.example 42
+terraform import truenas_user.example alice
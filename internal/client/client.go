@@ -16,7 +16,10 @@ import (
 )
 
 type Client struct {
-	conn    *websocket.Conn
+	conn     *websocket.Conn
+	readOnly bool
+	version  Version
+
 	writeMu sync.Mutex // serializes WriteJSON only
 	nextID  atomic.Int64
 
@@ -24,7 +27,7 @@ type Client struct {
 	pending   map[int64]chan rpcResponse
 
 	done    chan struct{} // closed when readLoop exits
-	doneErr error        // fatal error from readLoop
+	doneErr error         // fatal error from readLoop
 }
 
 type rpcRequest struct {
@@ -54,17 +57,34 @@ func (e *rpcError) Error() string {
 	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
 }
 
-func NewClient(ctx context.Context, wsURL, apiKey string, insecure bool) (*Client, error) {
-	url := strings.TrimRight(wsURL, "/") + "/api/current"
+// Options configures a Client created with NewClientWithOptions.
+type Options struct {
+	// TLSConfig is used for wss:// connections (e.g. with custom root CAs). Nil uses
+	// the defaults.
+	TLSConfig *tls.Config
 
-	tflog.Debug(ctx, "Connecting to TrueNAS WebSocket", map[string]any{"url": url})
+	// ReadOnly makes Call reject every method outside a read allowlist with an error
+	// wrapping ErrReadOnly.
+	ReadOnly bool
+}
 
-	dialer := websocket.Dialer{}
+func NewClient(ctx context.Context, wsURL, apiKey string, insecure bool) (*Client, error) {
+	var opts Options
 	if insecure {
-		dialer.TLSClientConfig = &tls.Config{
+		opts.TLSConfig = &tls.Config{
 			InsecureSkipVerify: true,
 		}
 	}
+	return NewClientWithOptions(ctx, wsURL, apiKey, opts)
+}
+
+// NewClientWithOptions is like NewClient but takes the full set of client options.
+func NewClientWithOptions(ctx context.Context, wsURL, apiKey string, opts Options) (*Client, error) {
+	url := strings.TrimRight(wsURL, "/") + "/api/current"
+
+	tflog.Debug(ctx, "Connecting to TrueNAS WebSocket", map[string]any{"url": url})
+
+	dialer := websocket.Dialer{TLSClientConfig: opts.TLSConfig}
 
 	header := http.Header{}
 	conn, _, err := dialer.DialContext(ctx, url, header)
@@ -73,9 +93,10 @@ func NewClient(ctx context.Context, wsURL, apiKey string, insecure bool) (*Clien
 	}
 
 	c := &Client{
-		conn:    conn,
-		pending: make(map[int64]chan rpcResponse),
-		done:    make(chan struct{}),
+		conn:     conn,
+		readOnly: opts.ReadOnly,
+		pending:  make(map[int64]chan rpcResponse),
+		done:     make(chan struct{}),
 	}
 
 	go c.readLoop()
@@ -92,9 +113,13 @@ func NewClient(ctx context.Context, wsURL, apiKey string, insecure bool) (*Clien
 				"attempt": attempt + 1,
 				"backoff": backoff.String(),
 			})
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+				continue
+			case <-ctx.Done():
+				err = fmt.Errorf("cancelled while rate limited: %w", ctx.Err())
+			}
 		}
 		break
 	}
@@ -109,7 +134,14 @@ func NewClient(ctx context.Context, wsURL, apiKey string, insecure bool) (*Clien
 		return nil, fmt.Errorf("authentication failed: login returned false")
 	}
 
-	tflog.Debug(ctx, "Successfully connected and authenticated to TrueNAS")
+	var rawVersion string
+	if err := c.Call(ctx, "system.version", nil, &rawVersion); err != nil {
+		tflog.Warn(ctx, "Unable to read TrueNAS version, capability checks are disabled", map[string]any{"error": err.Error()})
+	} else if c.version, err = ParseVersion(rawVersion); err != nil {
+		tflog.Warn(ctx, "Unable to parse TrueNAS version, capability checks are disabled", map[string]any{"error": err.Error()})
+	}
+
+	tflog.Debug(ctx, "Successfully connected and authenticated to TrueNAS", map[string]any{"version": rawVersion})
 
 	return c, nil
 }
@@ -158,6 +190,10 @@ func (c *Client) readLoop() {
 }
 
 func (c *Client) Call(ctx context.Context, method string, params any, dest any) error {
+	if c.readOnly && !isReadOnlyMethod(method) {
+		return readOnlyError(method)
+	}
+
 	// Fail fast if readLoop has already exited
 	select {
 	case <-c.done:
@@ -215,6 +251,12 @@ func (c *Client) Call(ctx context.Context, method string, params any, dest any)
 	}
 }
 
+// ServerVersion returns the version of the connected TrueNAS server, read when the
+// client was created. It is the zero Version if the version could not be determined.
+func (c *Client) ServerVersion() Version {
+	return c.version
+}
+
 func (c *Client) Close() error {
 	err := c.conn.Close()
 	<-c.done
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// jobPollInterval is how often WaitJob polls core.get_jobs.
+var jobPollInterval = 2 * time.Second
+
+// Job is the state of a TrueNAS job, as returned by core.get_jobs.
+type Job struct {
+	ID       int64           `json:"id"`
+	Method   string          `json:"method"`
+	State    string          `json:"state"`
+	Error    string          `json:"error"`
+	Result   json.RawMessage `json:"result"`
+	Progress struct {
+		Percent     float64 `json:"percent"`
+		Description string  `json:"description"`
+	} `json:"progress"`
+}
+
+// done reports whether the job has finished, and returns its error if it failed.
+func (j *Job) done() (bool, error) {
+	switch j.State {
+	case "SUCCESS":
+		return true, nil
+	case "FAILED", "ABORTED":
+		msg := j.Error
+		if msg == "" {
+			msg = "no error message"
+		}
+		return true, fmt.Errorf("job %d (%s) %s: %s", j.ID, j.Method, j.State, msg)
+	default:
+		return false, nil
+	}
+}
+
+// CallJob calls a method that runs as a job, such as pool.create, waits for the job to
+// finish and decodes its result into dest (if non-nil).
+func (c *Client) CallJob(ctx context.Context, method string, params any, dest any) error {
+	var jobID int64
+	if err := c.Call(ctx, method, params, &jobID); err != nil {
+		return err
+	}
+	return c.WaitJob(ctx, jobID, dest)
+}
+
+// WaitJob polls a job until it finishes or ctx is done, and decodes its result into
+// dest (if non-nil). A failed or aborted job is returned as an error.
+func (c *Client) WaitJob(ctx context.Context, jobID int64, dest any) error {
+	for {
+		var jobs []Job
+		if err := c.Call(ctx, "core.get_jobs", []any{[]any{[]any{"id", "=", jobID}}}, &jobs); err != nil {
+			return fmt.Errorf("unable to read the state of job %d: %w", jobID, err)
+		}
+		if len(jobs) == 0 {
+			return fmt.Errorf("job %d not found", jobID)
+		}
+
+		job := jobs[0]
+		done, err := job.done()
+		if err != nil {
+			return err
+		}
+		if done {
+			if dest != nil && len(job.Result) > 0 {
+				if err := json.Unmarshal(job.Result, dest); err != nil {
+					return fmt.Errorf("failed to unmarshal result of job %d (%s): %w", jobID, job.Method, err)
+				}
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for job %d (%s, %.0f%% done) cancelled: %w", jobID, job.Method, job.Progress.Percent, ctx.Err())
+		case <-time.After(jobPollInterval):
+		}
+	}
+}
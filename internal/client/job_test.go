@@ -0,0 +1,35 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJobDone(t *testing.T) {
+	tests := []struct {
+		job      Job
+		wantDone bool
+		wantErr  string
+	}{
+		{job: Job{ID: 1, Method: "pool.create", State: "WAITING"}},
+		{job: Job{ID: 1, Method: "pool.create", State: "RUNNING"}},
+		{job: Job{ID: 1, Method: "pool.create", State: "SUCCESS"}, wantDone: true},
+		{job: Job{ID: 2, Method: "pool.export", State: "FAILED", Error: "[EBUSY] pool is busy"}, wantDone: true, wantErr: "job 2 (pool.export) FAILED: [EBUSY] pool is busy"},
+		{job: Job{ID: 3, Method: "pool.update", State: "ABORTED"}, wantDone: true, wantErr: "job 3 (pool.update) ABORTED: no error message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.job.State, func(t *testing.T) {
+			done, err := tt.job.done()
+			if done != tt.wantDone {
+				t.Errorf("done = %v, want %v", done, tt.wantDone)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrReadOnly is returned (wrapped) by Call when a read-only client is asked to call a
+// method that may change the system.
+var ErrReadOnly = errors.New("read-only mode")
+
+// readOnlyMethodSuffixes are method name suffixes that only read data.
+var readOnlyMethodSuffixes = []string{
+	".query",
+	".get_instance",
+	".config",
+	"_choices",
+}
+
+// readOnlyMethods are individual methods that only read data, or that are needed to
+// establish a session.
+var readOnlyMethods = map[string]bool{
+	"auth.login_with_api_key":  true,
+	"auth.me":                  true,
+	"core.get_jobs":            true,
+	"core.ping":                true,
+	"pool.dataset.attachments": true,
+	"pool.dataset.export_key":  true,
+	"system.info":              true,
+	"system.version":           true,
+	"system.version_short":     true,
+	"system.ready":             true,
+	"system.state":             true,
+}
+
+// isReadOnlyMethod reports whether method is on the read allowlist.
+func isReadOnlyMethod(method string) bool {
+	if readOnlyMethods[method] {
+		return true
+	}
+	for _, suffix := range readOnlyMethodSuffixes {
+		if strings.HasSuffix(method, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func readOnlyError(method string) error {
+	return fmt.Errorf("%w: refusing to call %s, which may modify the system", ErrReadOnly, method)
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	tests := map[string]bool{
+		"pool.dataset.query":               true,
+		"pool.dataset.get_instance":        true,
+		"iscsi.global.config":              true,
+		"pool.dataset.compression_choices": true,
+		"iscsi.extent.disk_choices":        true,
+		"auth.me":                          true,
+		"auth.login_with_api_key":          true,
+		"system.version":                   true,
+		"core.get_jobs":                    true,
+		"pool.dataset.attachments":         true,
+		"pool.dataset.export_key":          true,
+		"pool.dataset.create":              false,
+		"pool.dataset.update":              false,
+		"pool.dataset.delete":              false,
+		"service.control":                  false,
+		"iscsi.global.update":              false,
+		"pool.export":                      false,
+		"core.job_abort":                   false,
+		"pool.dataset.query_and_delete":    false,
+		"auth.generate_token":              false,
+		"user.shell_choices_and_then_drop": false,
+	}
+
+	for method, want := range tests {
+		if got := isReadOnlyMethod(method); got != want {
+			t.Errorf("isReadOnlyMethod(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestCallReadOnly(t *testing.T) {
+	// A read-only client rejects mutating methods before anything is sent, so no
+	// connection is needed.
+	c := &Client{
+		readOnly: true,
+		pending:  make(map[int64]chan rpcResponse),
+		done:     make(chan struct{}),
+	}
+
+	err := c.Call(context.Background(), "pool.dataset.delete", []any{"tank/data"}, nil)
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("Call() error = %v, want ErrReadOnly", err)
+	}
+	if !strings.Contains(err.Error(), "pool.dataset.delete") {
+		t.Errorf("error %q does not name the blocked method", err)
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Version is a TrueNAS release version, e.g. 25.10.1. The zero Version means the
+// version is unknown.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	Raw   string // as returned by system.version, e.g. "TrueNAS-SCALE-24.10.2"
+}
+
+var versionRegexp = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion extracts the release number from a system.version string such as
+// "TrueNAS-SCALE-24.10.2" or "25.10.0".
+func ParseVersion(s string) (Version, error) {
+	m := versionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("unrecognized TrueNAS version %q", s)
+	}
+
+	v := Version{Raw: s}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// IsZero reports whether the version is unknown.
+func (v Version) IsZero() bool {
+	return v.Major == 0 && v.Minor == 0 && v.Patch == 0
+}
+
+// AtLeast reports whether v is the same as or newer than major.minor.
+func (v Version) AtLeast(major, minor int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	return v.Minor >= minor
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
@@ -0,0 +1,56 @@
+package client
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr bool
+	}{
+		{in: "TrueNAS-SCALE-24.10.2", want: Version{Major: 24, Minor: 10, Patch: 2}},
+		{in: "TrueNAS-25.04.1", want: Version{Major: 25, Minor: 4, Patch: 1}},
+		{in: "25.10.0", want: Version{Major: 25, Minor: 10}},
+		{in: "25.10-MASTER-20250901", want: Version{Major: 25, Minor: 10}},
+		{in: "TrueNAS-SCALE-Dragonfish", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseVersion(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			tt.want.Raw = tt.in
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	v := Version{Major: 25, Minor: 4, Patch: 2}
+
+	tests := []struct {
+		major, minor int
+		want         bool
+	}{
+		{24, 10, true},
+		{25, 4, true},
+		{25, 10, false},
+		{26, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got := v.AtLeast(tt.major, tt.minor); got != tt.want {
+			t.Errorf("%s.AtLeast(%d, %d) = %v, want %v", v, tt.major, tt.minor, got, tt.want)
+		}
+	}
+}
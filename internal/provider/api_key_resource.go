@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
@@ -14,8 +16,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
 )
 
 // truenasDate handles TrueNAS date fields that may be either a plain string
@@ -50,35 +50,15 @@ func (d *truenasDate) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("unexpected date format: %s", string(data))
 }
 
-var (
-	_ resource.Resource                = (*apiKeyResource)(nil)
-	_ resource.ResourceWithConfigure   = (*apiKeyResource)(nil)
-	_ resource.ResourceWithImportState = (*apiKeyResource)(nil)
-)
-
-type apiKeyResource struct {
-	client *client.Client
-}
-
 type apiKeyResourceModel struct {
-	ID        types.Int64  `tfsdk:"id"`
-	Name      types.String `tfsdk:"name"`
-	Username  types.String `tfsdk:"username"`
-	ExpiresAt types.String `tfsdk:"expires_at"`
-	Key       types.String `tfsdk:"key"`
-	CreatedAt types.String `tfsdk:"created_at"`
-	Revoked   types.Bool   `tfsdk:"revoked"`
-}
-
-type apiKeyCreateParams struct {
-	Name      string `json:"name"`
-	Username  string `json:"username,omitempty"`
-	ExpiresAt string `json:"expires_at,omitempty"`
-}
-
-type apiKeyUpdateParams struct {
-	Name      string `json:"name,omitempty"`
-	ExpiresAt string `json:"expires_at,omitempty"`
+	ID        types.Int64    `tfsdk:"id"`
+	Name      types.String   `tfsdk:"name"`
+	Username  types.String   `tfsdk:"username"`
+	ExpiresAt types.String   `tfsdk:"expires_at"`
+	Key       types.String   `tfsdk:"key"`
+	CreatedAt types.String   `tfsdk:"created_at"`
+	Revoked   types.Bool     `tfsdk:"revoked"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 type apiKeyResult struct {
@@ -92,15 +72,20 @@ type apiKeyResult struct {
 }
 
 func NewAPIKeyResource() resource.Resource {
-	return &apiKeyResource{}
-}
-
-func (r *apiKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_api_key"
-}
-
-func (r *apiKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+	return newCRUDResource(crudSpec[apiKeyResourceModel, apiKeyResult]{
+		typeName:     "api_key",
+		namespace:    "api_key",
+		kind:         "API Key",
+		schema:       apiKeySchema,
+		params:       apiKeyParams,
+		populate:     populateAPIKeyState,
+		importLookup: lookupAPIKey,
+		modifyPlan:   planAPIKeyUsername,
+	})
+}
+
+func apiKeySchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		MarkdownDescription: "Manages a TrueNAS API key.\n\n" +
 			"~> **Important:** The `key` attribute is only returned when the API key is first created. " +
 			"On subsequent reads, the value is preserved from Terraform state. After `terraform import`, `key` will be null.",
@@ -150,188 +135,58 @@ func (r *apiKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *apiKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
-	}
-
-	r.client = c
-}
-
-func (r *apiKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan apiKeyResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	username := plan.Username.ValueString()
-	if plan.Username.IsNull() || plan.Username.IsUnknown() || username == "" {
-		// TrueNAS 25.10+ requires username; look up the authenticated user
-		var me struct {
-			Username string `json:"pw_name"`
-		}
-		if err := r.client.Call(ctx, "auth.me", nil, &me); err != nil {
-			resp.Diagnostics.AddError("Error Looking Up Authenticated User", err.Error())
-			return
-		}
-		username = me.Username
-	}
-
-	params := apiKeyCreateParams{
-		Name:     plan.Name.ValueString(),
-		Username: username,
-	}
-	if !plan.ExpiresAt.IsNull() {
-		params.ExpiresAt = plan.ExpiresAt.ValueString()
-	}
-
-	var result apiKeyResult
-	err := r.client.Call(ctx, "api_key.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating API Key", err.Error())
-		return
-	}
-
-	plan.ID = types.Int64Value(result.ID)
-	plan.Name = types.StringValue(result.Name)
-	plan.Username = types.StringValue(result.Username)
-	plan.Key = types.StringValue(result.Key)
-	plan.CreatedAt = types.StringValue(result.CreatedAt.Value)
-	plan.Revoked = types.BoolValue(result.Revoked)
-	if result.ExpiresAt.Value != "" {
-		plan.ExpiresAt = types.StringValue(result.ExpiresAt.Value)
-	} else {
-		plan.ExpiresAt = types.StringNull()
-	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *apiKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state apiKeyResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
+// planAPIKeyUsername plans the authenticated user as the owner of a new key without a
+// username, as TrueNAS 25.10+ requires one.
+func planAPIKeyUsername(ctx context.Context, d *providerData, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var username types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("username"), &username)...)
+	if d == nil || !req.State.Raw.IsNull() || !username.IsUnknown() {
 		return
 	}
 
-	var results []apiKeyResult
-	err := r.client.Call(ctx, "api_key.query", []any{
-		[]any{[]any{"id", "=", state.ID.ValueInt64()}},
-	}, &results)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Reading API Key", err.Error())
-		return
+	var me struct {
+		Username string `json:"pw_name"`
 	}
-
-	if len(results) == 0 {
-		resp.State.RemoveResource(ctx)
+	if err := d.client.Call(ctx, "auth.me", nil, &me); err != nil {
+		resp.Diagnostics.AddError("Error Looking Up Authenticated User", err.Error())
 		return
 	}
-
-	result := results[0]
-	// Preserve key from prior state since it cannot be re-fetched
-	priorKey := state.Key
-
-	state.ID = types.Int64Value(result.ID)
-	state.Name = types.StringValue(result.Name)
-	state.Username = types.StringValue(result.Username)
-	state.Key = priorKey
-	state.CreatedAt = types.StringValue(result.CreatedAt.Value)
-	state.Revoked = types.BoolValue(result.Revoked)
-	if result.ExpiresAt.Value != "" {
-		state.ExpiresAt = types.StringValue(result.ExpiresAt.Value)
-	} else {
-		state.ExpiresAt = types.StringNull()
-	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("username"), me.Username)...)
 }
 
-func (r *apiKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan apiKeyResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
+func apiKeyParams(_ context.Context, plan, state *apiKeyResourceModel, _ *diag.Diagnostics) map[string]any {
+	params := map[string]any{
+		"name": plan.Name.ValueString(),
 	}
-
-	var state apiKeyResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := apiKeyUpdateParams{
-		Name: plan.Name.ValueString(),
+	// The owner is only set on creation.
+	if state == nil && !plan.Username.IsUnknown() {
+		params["username"] = plan.Username.ValueString()
 	}
 	if !plan.ExpiresAt.IsNull() {
-		params.ExpiresAt = plan.ExpiresAt.ValueString()
+		params["expires_at"] = plan.ExpiresAt.ValueString()
 	}
+	return params
+}
 
-	var result apiKeyResult
-	err := r.client.Call(ctx, "api_key.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating API Key", err.Error())
-		return
+// populateAPIKeyState copies an API key into the model. The key itself is only returned
+// on creation; afterwards the value already in the model is kept.
+func populateAPIKeyState(_ context.Context, model *apiKeyResourceModel, result *apiKeyResult, _ *diag.Diagnostics) {
+	model.ID = types.Int64Value(result.ID)
+	model.Name = types.StringValue(result.Name)
+	model.Username = types.StringValue(result.Username)
+	if result.Key != "" {
+		model.Key = types.StringValue(result.Key)
 	}
-
-	// Preserve key from prior state since update without reset doesn't return it
-	plan.ID = types.Int64Value(result.ID)
-	plan.Name = types.StringValue(result.Name)
-	plan.Username = types.StringValue(result.Username)
-	plan.Key = state.Key
-	plan.CreatedAt = types.StringValue(result.CreatedAt.Value)
-	plan.Revoked = types.BoolValue(result.Revoked)
+	model.CreatedAt = types.StringValue(result.CreatedAt.Value)
+	model.Revoked = types.BoolValue(result.Revoked)
 	if result.ExpiresAt.Value != "" {
-		plan.ExpiresAt = types.StringValue(result.ExpiresAt.Value)
+		model.ExpiresAt = types.StringValue(result.ExpiresAt.Value)
 	} else {
-		plan.ExpiresAt = types.StringNull()
+		model.ExpiresAt = types.StringNull()
 	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *apiKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state apiKeyResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	err := r.client.Call(ctx, "api_key.delete", []any{state.ID.ValueInt64()}, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting API Key", err.Error())
-		return
-	}
-}
-
-func (r *apiKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing API Key",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	state := apiKeyResourceModel{
-		ID:  types.Int64Value(id),
-		Key: types.StringNull(),
-	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+// capability is a feature that only exists on some TrueNAS releases.
+type capability struct {
+	description string // used in diagnostics, e.g. "nvmet"
+	major       int    // first release that supports the feature
+	minor       int
+}
+
+// Known capabilities, keyed by name.
+const (
+	capNVMeT              = "nvmet"
+	capSMBVeeamRepository = "smb_veeam_repository_share"
+)
+
+var capabilities = map[string]capability{
+	capNVMeT:              {description: "nvmet", major: 25, minor: 10},
+	capSMBVeeamRepository: {description: `SMB share purpose "VEEAM_REPOSITORY_SHARE"`, major: 25, minor: 10},
+}
+
+// capabilityNote returns a sentence for schema descriptions stating the minimum release.
+func capabilityNote(name string) string {
+	feature := capabilities[name]
+	return fmt.Sprintf("Requires TrueNAS %d.%02d or later.", feature.major, feature.minor)
+}
+
+// checkCapability returns an error if a server running version v lacks the named
+// capability. An unknown (zero) version passes, leaving the server to reject the call.
+func checkCapability(v client.Version, name string) error {
+	feature, ok := capabilities[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown capability %q", name))
+	}
+	if v.IsZero() || v.AtLeast(feature.major, feature.minor) {
+		return nil
+	}
+	return fmt.Errorf("%s requires TrueNAS %d.%02d+, but the connected server runs %d.%02d (%s)",
+		feature.description, feature.major, feature.minor, v.Major, v.Minor, v.Raw)
+}
+
+// requireCapability adds an error diagnostic if the connected server lacks the named
+// capability. It is meant to be called from ModifyPlan so the problem is reported at
+// plan time rather than as an unknown method during apply.
+func requireCapability(c *client.Client, name string, diags *diag.Diagnostics) {
+	if c == nil {
+		return
+	}
+	if err := checkCapability(c.ServerVersion(), name); err != nil {
+		diags.AddError("Unsupported TrueNAS Version", err.Error())
+	}
+}
+
+// requireAttributeCapability is like requireCapability, for a single attribute value.
+func requireAttributeCapability(c *client.Client, name string, attrPath path.Path, diags *diag.Diagnostics) {
+	if c == nil {
+		return
+	}
+	if err := checkCapability(c.ServerVersion(), name); err != nil {
+		diags.AddAttributeError(attrPath, "Unsupported TrueNAS Version", err.Error())
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+func TestCheckCapability(t *testing.T) {
+	tests := []struct {
+		name    string
+		version client.Version
+		wantErr string
+	}{
+		{name: "unknown version", version: client.Version{}},
+		{name: "exact release", version: client.Version{Major: 25, Minor: 10, Raw: "25.10.0"}},
+		{name: "newer release", version: client.Version{Major: 26, Minor: 4, Raw: "26.04.0"}},
+		{
+			name:    "older release",
+			version: client.Version{Major: 25, Minor: 4, Patch: 2, Raw: "TrueNAS-25.04.2"},
+			wantErr: "nvmet requires TrueNAS 25.10+, but the connected server runs 25.04 (TrueNAS-25.04.2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCapability(tt.version, capNVMeT)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCapabilityNote(t *testing.T) {
+	if got, want := capabilityNote(capNVMeT), "Requires TrueNAS 25.10 or later."; got != want {
+		t.Errorf("capabilityNote() = %q, want %q", got, want)
+	}
+}
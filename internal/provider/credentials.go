@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// apiKeySource describes where the API key comes from. At most one field is set.
+type apiKeySource struct {
+	APIKey            string
+	APIKeyFile        string
+	CredentialProcess string
+}
+
+func (s apiKeySource) isSet() bool {
+	return s.APIKey != "" || s.APIKeyFile != "" || s.CredentialProcess != ""
+}
+
+// override replaces the current source with next if next is set. Sources from different
+// levels (profile, environment, provider block) never combine: the highest level that
+// sets any of them wins. Setting more than one within the same level is an error.
+func (s *apiKeySource) override(level string, next apiKeySource) error {
+	n := 0
+	for _, v := range []string{next.APIKey, next.APIKeyFile, next.CredentialProcess} {
+		if v != "" {
+			n++
+		}
+	}
+	if n > 1 {
+		return fmt.Errorf("%s sets more than one of api_key, api_key_file and credential_process", level)
+	}
+	if n == 1 {
+		*s = next
+	}
+	return nil
+}
+
+// credentialProcessOutput is the JSON document a credential_process command must print.
+type credentialProcessOutput struct {
+	Version int    `json:"version"`
+	APIKey  string `json:"api_key"`
+}
+
+// load returns the API key, reading the key file or running the credential process as
+// needed. It is called on every Configure so that a rotated key is picked up.
+func (s apiKeySource) load(ctx context.Context) (string, error) {
+	switch {
+	case s.APIKeyFile != "":
+		data, err := os.ReadFile(s.APIKeyFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read API key file: %w", err)
+		}
+		key := strings.TrimSpace(string(data))
+		if key == "" {
+			return "", fmt.Errorf("API key file %s is empty", s.APIKeyFile)
+		}
+		return key, nil
+
+	case s.CredentialProcess != "":
+		return runCredentialProcess(ctx, s.CredentialProcess)
+
+	default:
+		return s.APIKey, nil
+	}
+}
+
+// runCredentialProcess runs command through the system shell and parses the API key from
+// its JSON output, e.g. {"version": 1, "api_key": "1-abcdef"}.
+func runCredentialProcess(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("credential_process failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("credential_process failed: %w", err)
+	}
+
+	var out credentialProcessOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return "", fmt.Errorf("credential_process output is not valid JSON: %w", err)
+	}
+	if out.Version != 0 && out.Version != 1 {
+		return "", fmt.Errorf("credential_process output has unsupported version %d", out.Version)
+	}
+	if out.APIKey == "" {
+		return "", fmt.Errorf("credential_process output has no api_key")
+	}
+	return out.APIKey, nil
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestAPIKeySourceLoadFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte("1-from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := apiKeySource{APIKeyFile: keyFile}.load(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if key != "1-from-file" {
+		t.Errorf("got %q, want %q", key, "1-from-file")
+	}
+
+	// A rotated key is picked up on the next load.
+	if err := os.WriteFile(keyFile, []byte("2-rotated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	key, err = apiKeySource{APIKeyFile: keyFile}.load(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if key != "2-rotated" {
+		t.Errorf("got %q, want %q", key, "2-rotated")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(empty, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (apiKeySource{APIKeyFile: empty}).load(context.Background()); err == nil {
+		t.Error("expected error for empty key file")
+	}
+	if _, err := (apiKeySource{APIKeyFile: filepath.Join(t.TempDir(), "missing")}).load(context.Background()); err == nil {
+		t.Error("expected error for missing key file")
+	}
+}
+
+func TestAPIKeySourceLoadCredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use a POSIX shell")
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr bool
+	}{
+		{name: "with version", command: `echo '{"version": 1, "api_key": "1-abc"}'`, want: "1-abc"},
+		{name: "without version", command: `printf '{"api_key": "2-def"}'`, want: "2-def"},
+		{name: "unsupported version", command: `echo '{"version": 2, "api_key": "x"}'`, wantErr: true},
+		{name: "missing key", command: `echo '{}'`, wantErr: true},
+		{name: "not json", command: `echo 1-abc`, wantErr: true},
+		{name: "failing command", command: `echo denied >&2; exit 3`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apiKeySource{CredentialProcess: tt.command}.load(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
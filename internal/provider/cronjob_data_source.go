@@ -93,7 +93,7 @@ func (d *cronjobDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 						Computed:    true,
 					},
 					"dow": schema.StringAttribute{
-						Description: "Day of week field (0-6, *, or cron expression).",
+						Description: "Day of week field (0-7, where 0 and 7 are Sunday, *, or cron expression).",
 						Computed:    true,
 					},
 				},
@@ -107,16 +107,16 @@ func (d *cronjobDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = c
+	d.client = data.client
 }
 
 func (d *cronjobDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
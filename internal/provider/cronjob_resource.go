@@ -2,42 +2,31 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
-)
-
-var (
-	_ resource.Resource                = (*cronjobResource)(nil)
-	_ resource.ResourceWithConfigure   = (*cronjobResource)(nil)
-	_ resource.ResourceWithImportState = (*cronjobResource)(nil)
 )
 
-type cronjobResource struct {
-	client *client.Client
-}
-
 type cronjobResourceModel struct {
-	ID          types.Int64  `tfsdk:"id"`
-	Command     types.String `tfsdk:"command"`
-	User        types.String `tfsdk:"user"`
-	Description types.String `tfsdk:"description"`
-	Enabled     types.Bool   `tfsdk:"enabled"`
-	Stdout      types.Bool   `tfsdk:"stdout"`
-	Stderr      types.Bool   `tfsdk:"stderr"`
-	Schedule    types.Object `tfsdk:"schedule"`
+	ID           types.Int64    `tfsdk:"id"`
+	Command      types.String   `tfsdk:"command"`
+	User         types.String   `tfsdk:"user"`
+	Description  types.String   `tfsdk:"description"`
+	Enabled      types.Bool     `tfsdk:"enabled"`
+	Stdout       types.Bool     `tfsdk:"stdout"`
+	Stderr       types.Bool     `tfsdk:"stderr"`
+	Schedule     types.Object   `tfsdk:"schedule"`
+	OwnershipTag types.String   `tfsdk:"ownership_tag"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
 }
 
 type cronjobScheduleModel struct {
@@ -56,16 +45,6 @@ var cronjobScheduleAttrTypes = map[string]attr.Type{
 	"dow":    types.StringType,
 }
 
-type cronjobCreateParams struct {
-	Command     string          `json:"command"`
-	User        string          `json:"user"`
-	Description string          `json:"description"`
-	Enabled     bool            `json:"enabled"`
-	Stdout      bool            `json:"stdout"`
-	Stderr      bool            `json:"stderr"`
-	Schedule    cronjobSchedule `json:"schedule"`
-}
-
 type cronjobSchedule struct {
 	Minute string `json:"minute"`
 	Hour   string `json:"hour"`
@@ -86,15 +65,24 @@ type cronjobResult struct {
 }
 
 func NewCronjobResource() resource.Resource {
-	return &cronjobResource{}
-}
-
-func (r *cronjobResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_cronjob"
+	return newCRUDResource(crudSpec[cronjobResourceModel, cronjobResult]{
+		typeName:     "cronjob",
+		namespace:    "cronjob",
+		kind:         "Cron Job",
+		schema:       cronjobSchema,
+		params:       cronjobParams,
+		populate:     populateCronjobState,
+		importLookup: lookupCronjob,
+		modifyPlan:   planOwnershipTag,
+		importOwnership: func(result *cronjobResult) (string, string) {
+			_, tag := splitOwnershipMarker(result.Description)
+			return result.Command, tag
+		},
+	})
 }
 
-func (r *cronjobResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func cronjobSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		Description: "Manages a TrueNAS cron job.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -141,140 +129,58 @@ func (r *cronjobResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					"minute": schema.StringAttribute{
 						Description: "Minute field (0-59, *, or cron expression).",
 						Required:    true,
+						Validators: []validator.String{
+							validCronField("minute"),
+						},
 					},
 					"hour": schema.StringAttribute{
 						Description: "Hour field (0-23, *, or cron expression).",
 						Required:    true,
+						Validators: []validator.String{
+							validCronField("hour"),
+						},
 					},
 					"dom": schema.StringAttribute{
 						Description: "Day of month field (1-31, *, or cron expression).",
 						Required:    true,
+						Validators: []validator.String{
+							validCronField("dom"),
+						},
 					},
 					"month": schema.StringAttribute{
 						Description: "Month field (1-12, *, or cron expression).",
 						Required:    true,
+						Validators: []validator.String{
+							validCronField("month"),
+						},
 					},
 					"dow": schema.StringAttribute{
-						Description: "Day of week field (0-6, *, or cron expression).",
+						Description: "Day of week field (0-7, where 0 and 7 are Sunday, *, or cron expression).",
 						Required:    true,
+						Validators: []validator.String{
+							validCronField("dow"),
+						},
 					},
 				},
 			},
+			"ownership_tag": ownershipTagAttribute("cron job", "at the end of its description, as [terraform:<tag>]"),
+			"timeouts":      timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *cronjobResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
-	}
-
-	r.client = c
-}
-
-func (r *cronjobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan cronjobResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var sched cronjobScheduleModel
-	resp.Diagnostics.Append(plan.Schedule.As(ctx, &sched, basetypes.ObjectAsOptions{})...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := cronjobCreateParams{
-		Command: plan.Command.ValueString(),
-		User:    plan.User.ValueString(),
-		Schedule: cronjobSchedule{
-			Minute: sched.Minute.ValueString(),
-			Hour:   sched.Hour.ValueString(),
-			Dom:    sched.Dom.ValueString(),
-			Month:  sched.Month.ValueString(),
-			Dow:    sched.Dow.ValueString(),
-		},
-	}
-	params.Description = plan.Description.ValueString()
-	params.Enabled = plan.Enabled.ValueBool()
-	params.Stdout = plan.Stdout.ValueBool()
-	params.Stderr = plan.Stderr.ValueBool()
-
-	var result cronjobResult
-	err := r.client.Call(ctx, "cronjob.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Cron Job", err.Error())
-		return
-	}
-
-	resp.Diagnostics.Append(setCronjobResourceState(&plan, &result)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *cronjobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state cronjobResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var results []cronjobResult
-	err := r.client.Call(ctx, "cronjob.query", []any{
-		[]any{[]any{"id", "=", state.ID.ValueInt64()}},
-	}, &results)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Reading Cron Job", err.Error())
-		return
-	}
-
-	if len(results) == 0 {
-		resp.State.RemoveResource(ctx)
-		return
-	}
-
-	resp.Diagnostics.Append(setCronjobResourceState(&state, &results[0])...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-}
-
-func (r *cronjobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan cronjobResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var state cronjobResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
+func cronjobParams(ctx context.Context, plan, _ *cronjobResourceModel, diags *diag.Diagnostics) map[string]any {
 	var sched cronjobScheduleModel
-	resp.Diagnostics.Append(plan.Schedule.As(ctx, &sched, basetypes.ObjectAsOptions{})...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := cronjobCreateParams{
-		Command: plan.Command.ValueString(),
-		User:    plan.User.ValueString(),
-		Schedule: cronjobSchedule{
+	diags.Append(plan.Schedule.As(ctx, &sched, basetypes.ObjectAsOptions{})...)
+
+	return map[string]any{
+		"command":     plan.Command.ValueString(),
+		"user":        plan.User.ValueString(),
+		"description": withOwnershipMarker(plan.Description.ValueString(), plan.OwnershipTag.ValueString()),
+		"enabled":     plan.Enabled.ValueBool(),
+		"stdout":      plan.Stdout.ValueBool(),
+		"stderr":      plan.Stderr.ValueBool(),
+		"schedule": cronjobSchedule{
 			Minute: sched.Minute.ValueString(),
 			Hour:   sched.Hour.ValueString(),
 			Dom:    sched.Dom.ValueString(),
@@ -282,72 +188,34 @@ func (r *cronjobResource) Update(ctx context.Context, req resource.UpdateRequest
 			Dow:    sched.Dow.ValueString(),
 		},
 	}
-	params.Description = plan.Description.ValueString()
-	params.Enabled = plan.Enabled.ValueBool()
-	params.Stdout = plan.Stdout.ValueBool()
-	params.Stderr = plan.Stderr.ValueBool()
-
-	var result cronjobResult
-	err := r.client.Call(ctx, "cronjob.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating Cron Job", err.Error())
-		return
-	}
-
-	resp.Diagnostics.Append(setCronjobResourceState(&plan, &result)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *cronjobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state cronjobResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	err := r.client.Call(ctx, "cronjob.delete", []any{state.ID.ValueInt64()}, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting Cron Job", err.Error())
-		return
-	}
 }
 
-func (r *cronjobResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing Cron Job",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.Int64Value(id))...)
-}
-
-func setCronjobResourceState(model *cronjobResourceModel, result *cronjobResult) diag.Diagnostics {
+func populateCronjobState(_ context.Context, model *cronjobResourceModel, result *cronjobResult, diags *diag.Diagnostics) {
 	model.ID = types.Int64Value(result.ID)
 	model.Command = types.StringValue(result.Command)
 	model.User = types.StringValue(result.User)
-	if result.Description != "" {
-		model.Description = types.StringValue(result.Description)
+	description, tag := splitOwnershipMarker(result.Description)
+	if description != "" {
+		model.Description = types.StringValue(description)
 	} else {
 		model.Description = types.StringNull()
 	}
+	if tag != "" {
+		model.OwnershipTag = types.StringValue(tag)
+	} else {
+		model.OwnershipTag = types.StringNull()
+	}
 	model.Enabled = types.BoolValue(result.Enabled)
 	model.Stdout = types.BoolValue(result.Stdout)
 	model.Stderr = types.BoolValue(result.Stderr)
 
-	scheduleValue, diags := types.ObjectValue(cronjobScheduleAttrTypes, map[string]attr.Value{
+	scheduleValue, d := types.ObjectValue(cronjobScheduleAttrTypes, map[string]attr.Value{
 		"minute": types.StringValue(result.Schedule.Minute),
 		"hour":   types.StringValue(result.Schedule.Hour),
 		"dom":    types.StringValue(result.Schedule.Dom),
 		"month":  types.StringValue(result.Schedule.Month),
 		"dow":    types.StringValue(result.Schedule.Dow),
 	})
+	diags.Append(d...)
 	model.Schedule = scheduleValue
-	return diags
 }
@@ -77,6 +77,12 @@ func TestAccCronjobResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "truenas_cronjob.test",
+				ImportState:       true,
+				ImportStateId:     "root:echo hello",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -0,0 +1,298 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+var (
+	_ resource.Resource                = (*crudResource[struct{}, struct{}])(nil)
+	_ resource.ResourceWithConfigure   = (*crudResource[struct{}, struct{}])(nil)
+	_ resource.ResourceWithImportState = (*crudResource[struct{}, struct{}])(nil)
+	_ resource.ResourceWithModifyPlan  = (*crudResource[struct{}, struct{}])(nil)
+)
+
+// crudSpec describes a TrueNAS object that is managed with the standard
+// <namespace>.create, .get_instance, .update and .delete methods and identified by an
+// integer id. M is the Terraform model, which must have "id" (types.Int64) and
+// "timeouts" attributes, and R is the API result the object decodes into.
+type crudSpec[M, R any] struct {
+	typeName  string // resource type name without the provider prefix, e.g. "nvmet_host"
+	namespace string // API namespace, e.g. "nvmet.host"
+	kind      string // object name used in diagnostics, e.g. "NVMe-oF Host"
+
+	schema func(ctx context.Context) schema.Schema
+
+	// params builds the create (state is nil) or update parameters from the plan.
+	params func(ctx context.Context, plan, state *M, diags *diag.Diagnostics) map[string]any
+
+	// populate copies an API result into the model.
+	populate func(ctx context.Context, model *M, result *R, diags *diag.Diagnostics)
+
+	// importLookup resolves natural import keys. Without one, only numeric IDs import.
+	importLookup importLookup
+
+	// capability, if set, must be supported by the server; it is checked at plan time.
+	capability string
+
+	// modifyPlan, if set, runs after the capability check for non-destroy plans. The
+	// provider data is nil until the provider is configured.
+	modifyPlan func(ctx context.Context, d *providerData, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse)
+
+	// importOwnership, if set, returns the name and ownership tag of an object being
+	// imported, so that importing an object tagged by another workspace warns.
+	importOwnership func(result *R) (name, tag string)
+
+	// deleteArgs, if set, returns the arguments passed to <namespace>.delete after the ID.
+	deleteArgs func(state *M) []any
+}
+
+// crudResource implements resource.Resource for a crudSpec. Resources built on it share
+// the same behaviour: a missing object is removed from state on read and ignored on
+// delete, every operation honours the timeouts attribute, and import accepts an ID or
+// a natural key.
+type crudResource[M, R any] struct {
+	spec     crudSpec[M, R]
+	client   *client.Client
+	provider *providerData
+}
+
+func newCRUDResource[M, R any](spec crudSpec[M, R]) resource.Resource {
+	return &crudResource[M, R]{spec: spec}
+}
+
+func (r *crudResource[M, R]) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.spec.typeName
+}
+
+func (r *crudResource[M, R]) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = r.spec.schema(ctx)
+}
+
+func (r *crudResource[M, R]) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.provider = data
+}
+
+func (r *crudResource[M, R]) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	if r.spec.capability != "" {
+		requireCapability(r.client, r.spec.capability, &resp.Diagnostics)
+	}
+	if r.spec.modifyPlan != nil && !resp.Diagnostics.HasError() {
+		r.spec.modifyPlan(ctx, r.provider, req, resp)
+	}
+}
+
+func (r *crudResource[M, R]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan M
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var t timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &t)...)
+	createTimeout, diags := t.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	params := r.spec.params(ctx, &plan, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var raw json.RawMessage
+	err := r.client.Call(ctx, r.spec.namespace+".create", []any{params}, &raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating "+r.spec.kind, err.Error())
+		return
+	}
+
+	var result R
+	if err := r.decodeResult(ctx, raw, &result); err != nil {
+		resp.Diagnostics.AddError("Error Reading "+r.spec.kind+" After Creation", err.Error())
+		return
+	}
+
+	r.spec.populate(ctx, &plan, &result, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *crudResource[M, R]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state M
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	id := r.stateID(ctx, req.State, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var result R
+	err := r.client.Call(ctx, r.spec.namespace+".get_instance", []any{id}, &result)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error Reading "+r.spec.kind, err.Error())
+		return
+	}
+
+	r.spec.populate(ctx, &state, &result, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *crudResource[M, R]) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state M
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	id := r.stateID(ctx, req.State, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var t timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &t)...)
+	updateTimeout, diags := t.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	params := r.spec.params(ctx, &plan, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var raw json.RawMessage
+	err := r.client.Call(ctx, r.spec.namespace+".update", []any{id, params}, &raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating "+r.spec.kind, err.Error())
+		return
+	}
+
+	var result R
+	if err := r.decodeResult(ctx, raw, &result); err != nil {
+		resp.Diagnostics.AddError("Error Reading "+r.spec.kind+" After Update", err.Error())
+		return
+	}
+
+	r.spec.populate(ctx, &plan, &result, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *crudResource[M, R]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	id := r.stateID(ctx, req.State, &resp.Diagnostics)
+	var t timeouts.Value
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("timeouts"), &t)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := t.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	args := []any{id}
+	if r.spec.deleteArgs != nil {
+		var state M
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		args = append(args, r.spec.deleteArgs(&state)...)
+	}
+
+	err := r.client.Call(ctx, r.spec.namespace+".delete", args, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError("Error Deleting "+r.spec.kind, err.Error())
+		return
+	}
+}
+
+func (r *crudResource[M, R]) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := resolveImportID(ctx, r.client, req.ID, r.spec.importLookup)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Importing "+r.spec.kind,
+			fmt.Sprintf("Could not resolve import ID %q: %s", req.ID, err.Error()),
+		)
+		return
+	}
+
+	// The ownership check reads the object, which is skipped before the provider is
+	// configured.
+	if r.spec.importOwnership != nil && r.client != nil {
+		var result R
+		if err := r.client.Call(ctx, r.spec.namespace+".get_instance", []any{id}, &result); err == nil {
+			name, tag := r.spec.importOwnership(&result)
+			checkImportOwnership(r.provider, r.spec.kind, name, tag, &resp.Diagnostics)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+func (r *crudResource[M, R]) stateID(ctx context.Context, state tfsdk.State, diags *diag.Diagnostics) int64 {
+	var id types.Int64
+	diags.Append(state.GetAttribute(ctx, path.Root("id"), &id)...)
+	return id.ValueInt64()
+}
+
+// decodeResult decodes the result of a create or update call. Some namespaces return
+// the object itself, others only its ID, in which case the object is fetched.
+func (r *crudResource[M, R]) decodeResult(ctx context.Context, raw json.RawMessage, result *R) error {
+	var id int64
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return json.Unmarshal(raw, result)
+	}
+	return r.client.Call(ctx, r.spec.namespace+".get_instance", []any{id}, result)
+}
+
+// isNotFound reports whether err is the error TrueNAS returns for a missing object.
+func isNotFound(err error) bool {
+	return strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found")
+}
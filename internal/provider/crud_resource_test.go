@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// TestCRUDResourceSchemas checks that every resource built on crudResource has the
+// "id" and "timeouts" attributes the generic implementation relies on.
+func TestCRUDResourceSchemas(t *testing.T) {
+	ctx := context.Background()
+
+	n := 0
+	for _, newResource := range New()().Resources(ctx) {
+		r := newResource()
+		if !isCRUDResource(r) {
+			continue
+		}
+		n++
+
+		var metaResp resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "truenas"}, &metaResp)
+
+		var schemaResp resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+		if _, ok := schemaResp.Schema.Attributes["id"].(schema.Int64Attribute); !ok {
+			t.Errorf("%s: expected an Int64 id attribute", metaResp.TypeName)
+		}
+		if _, ok := schemaResp.Schema.Attributes["timeouts"]; !ok {
+			t.Errorf("%s: expected a timeouts attribute", metaResp.TypeName)
+		}
+	}
+
+	if n == 0 {
+		t.Fatal("no resources are built on crudResource")
+	}
+}
+
+func isCRUDResource(r resource.Resource) bool {
+	return strings.HasPrefix(reflect.TypeOf(r).Elem().Name(), "crudResource[")
+}
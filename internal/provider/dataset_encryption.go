@@ -0,0 +1,349 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+// minPbkdf2Iters is the smallest number of PBKDF2 iterations TrueNAS accepts for a
+// passphrase.
+const minPbkdf2Iters = 100000
+
+// hexKeyPattern matches a hex-encoded 256-bit key.
+var hexKeyPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// replaceIfStateSetString requires replacement when a creation-only value changes,
+// except from a null state value, as found after an import or in state written before
+// the attribute existed.
+func replaceIfStateSetString() planmodifier.String {
+	const description = "Changing the value after creation requires replacing the dataset."
+	return stringplanmodifier.RequiresReplaceIf(func(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = !req.StateValue.IsNull()
+	}, description, description)
+}
+
+// replaceIfEncryptionEnabled requires replacement when an existing dataset becomes an
+// encryption root, which ZFS cannot do in place. The reverse change is an update.
+func replaceIfEncryptionEnabled() planmodifier.Bool {
+	const description = "Enabling encryption requires replacing the dataset."
+	return boolplanmodifier.RequiresReplaceIf(func(_ context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = !req.StateValue.IsNull() && !req.StateValue.ValueBool() && req.PlanValue.ValueBool()
+	}, description, description)
+}
+
+// datasetEncryptionAttributes returns the encryption attributes of truenas_pool_dataset.
+// The key and passphrase are write-only: they are sent on creation, on key changes and
+// when unlocking, and never stored in state.
+func datasetEncryptionAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"encryption": schema.BoolAttribute{
+			Description: "Make the dataset an encryption root, encrypted with encryption_key_wo, encryption_passphrase_wo or a generated key. " +
+				"Defaults to false, in which case the dataset inherits the encryption of its parent. Enabling it replaces the dataset; " +
+				"disabling it makes the dataset inherit the encryption properties of its encrypted parent in place.",
+			Optional: true,
+			Computed: true,
+			Default:  booldefault.StaticBool(false),
+			PlanModifiers: []planmodifier.Bool{
+				replaceIfEncryptionEnabled(),
+			},
+		},
+		"inherit_encryption": schema.BoolAttribute{
+			Description: "Whether the dataset inherits the encryption of its parent, which is the server's default. Set to false without encryption to create an unencrypted dataset, " +
+				"which TrueNAS only allows under an unencrypted parent. Must not be true when encryption is true. Only used during creation.",
+			Optional: true,
+		},
+		"encryption_algorithm": schema.StringAttribute{
+			Description: "Encryption algorithm, e.g. AES-256-GCM. Defaults to the server's default. Cannot be changed after creation.",
+			Optional:    true,
+			Computed:    true,
+			Validators: []validator.String{
+				stringvalidator.OneOf("AES-128-CCM", "AES-192-CCM", "AES-256-CCM", "AES-128-GCM", "AES-192-GCM", "AES-256-GCM"),
+			},
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+				replaceIfStateSetString(),
+			},
+		},
+		"encryption_generate_key": schema.BoolAttribute{
+			Description: "Have TrueNAS generate the encryption key and keep it in its database. Used during creation and when encryption_wo_version changes.",
+			Optional:    true,
+		},
+		"encryption_key_wo": schema.StringAttribute{
+			Description: "Hex-encoded 256-bit encryption key (64 hexadecimal characters). Write-only: used during creation and when encryption_wo_version changes, and never stored in state. Requires Terraform 1.11 or later.",
+			Optional:    true,
+			Sensitive:   true,
+			WriteOnly:   true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(hexKeyPattern, "must be 64 hexadecimal characters"),
+			},
+		},
+		"encryption_passphrase_wo": schema.StringAttribute{
+			Description: "Passphrase the encryption key is derived from (at least 8 characters). Write-only: used during creation, when encryption_wo_version changes and to unlock the dataset, and never stored in state. Requires Terraform 1.11 or later.",
+			Optional:    true,
+			Sensitive:   true,
+			WriteOnly:   true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(8),
+			},
+		},
+		"encryption_pbkdf2iters": schema.Int64Attribute{
+			Description: fmt.Sprintf("Number of PBKDF2 iterations used to derive the key from the passphrase (at least %d). Defaults to the server's default. "+
+				"Changing it re-wraps the key with encryption_passphrase_wo.", minPbkdf2Iters),
+			Optional: true,
+			Computed: true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(minPbkdf2Iters),
+			},
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
+			},
+		},
+		"encryption_wo_version": schema.Int64Attribute{
+			Description: "Change this value to apply the current encryption_key_wo, encryption_passphrase_wo or encryption_generate_key to the existing dataset " +
+				"with pool.dataset.change_key, e.g. to rotate the key or switch between a key and a passphrase. The data is not re-encrypted; only the key wrapping it changes.",
+			Optional: true,
+		},
+		"key_format": schema.StringAttribute{
+			Description: "Format of the encryption key of an encryption root: HEX or PASSPHRASE.",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"locked": schema.BoolAttribute{
+			Description: "Whether the dataset is locked, i.e. its key is unloaded and it is unmounted. Setting it locks or unlocks a passphrase-encrypted dataset; " +
+				"unlocking requires encryption_passphrase_wo.",
+			Optional: true,
+			Computed: true,
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+}
+
+// validateDatasetEncryption checks the combination of encryption attributes in config.
+func validateDatasetEncryption(config *poolDatasetResourceModel, diags *diag.Diagnostics) {
+	if config.Encryption.IsUnknown() || config.EncryptionKey.IsUnknown() || config.EncryptionPassphrase.IsUnknown() || config.EncryptionGenerateKey.IsUnknown() {
+		return
+	}
+
+	sources := 0
+	for _, set := range []bool{!config.EncryptionKey.IsNull(), !config.EncryptionPassphrase.IsNull(), config.EncryptionGenerateKey.ValueBool()} {
+		if set {
+			sources++
+		}
+	}
+
+	if !config.Encryption.ValueBool() {
+		settings := []struct {
+			name string
+			set  bool
+		}{
+			{"encryption_algorithm", !config.EncryptionAlgorithm.IsNull()},
+			{"encryption_generate_key", config.EncryptionGenerateKey.ValueBool()},
+			{"encryption_key_wo", !config.EncryptionKey.IsNull()},
+			{"encryption_passphrase_wo", !config.EncryptionPassphrase.IsNull()},
+			{"encryption_pbkdf2iters", !config.EncryptionPbkdf2iters.IsNull()},
+			{"encryption_wo_version", !config.EncryptionWOVersion.IsNull()},
+			{"locked", config.Locked.ValueBool()},
+		}
+		for _, s := range settings {
+			if s.set {
+				diags.AddAttributeError(path.Root(s.name), "Invalid Encryption Configuration",
+					fmt.Sprintf("%s can only be set when encryption is true.", s.name))
+			}
+		}
+		return
+	}
+
+	if sources != 1 {
+		diags.AddAttributeError(path.Root("encryption"), "Invalid Encryption Configuration",
+			"Exactly one of encryption_key_wo, encryption_passphrase_wo or encryption_generate_key = true must be set when encryption is true.")
+	}
+	if config.InheritEncryption.ValueBool() {
+		diags.AddAttributeError(path.Root("inherit_encryption"), "Invalid Encryption Configuration",
+			"inherit_encryption cannot be true when encryption is true.")
+	}
+	if !config.CloneFromSnapshot.IsNull() {
+		diags.AddAttributeError(path.Root("encryption"), "Invalid Encryption Configuration",
+			"A clone keeps the encryption of its origin; encryption cannot be set with clone_from_snapshot.")
+	}
+	if config.EncryptionPassphrase.IsNull() {
+		if !config.EncryptionPbkdf2iters.IsNull() {
+			diags.AddAttributeError(path.Root("encryption_pbkdf2iters"), "Invalid Encryption Configuration",
+				"encryption_pbkdf2iters only applies to passphrase encryption.")
+		}
+		if config.Locked.ValueBool() {
+			diags.AddAttributeError(path.Root("locked"), "Invalid Encryption Configuration",
+				"Only datasets encrypted with a passphrase can be locked.")
+		}
+	}
+}
+
+// setDatasetEncryptionParams adds the encryption parameters of pool.dataset.create to
+// params, taking the write-only secrets from config.
+func setDatasetEncryptionParams(params map[string]any, plan, config *poolDatasetResourceModel) {
+	if !plan.Encryption.ValueBool() {
+		if !plan.InheritEncryption.IsNull() {
+			params["inherit_encryption"] = plan.InheritEncryption.ValueBool()
+		}
+		return
+	}
+
+	options := map[string]any{
+		"generate_key": plan.EncryptionGenerateKey.ValueBool(),
+	}
+	if !config.EncryptionKey.IsNull() {
+		options["key"] = config.EncryptionKey.ValueString()
+	}
+	if !config.EncryptionPassphrase.IsNull() {
+		options["passphrase"] = config.EncryptionPassphrase.ValueString()
+	}
+	if !plan.EncryptionAlgorithm.IsNull() && !plan.EncryptionAlgorithm.IsUnknown() {
+		options["algorithm"] = plan.EncryptionAlgorithm.ValueString()
+	}
+	if !plan.EncryptionPbkdf2iters.IsNull() && !plan.EncryptionPbkdf2iters.IsUnknown() {
+		options["pbkdf2iters"] = plan.EncryptionPbkdf2iters.ValueInt64()
+	}
+
+	params["encryption"] = true
+	params["inherit_encryption"] = false
+	params["encryption_options"] = options
+}
+
+// populateDatasetEncryption updates the encryption attributes of model from result.
+// Creation-only attributes are kept, and write-only ones are always null in state.
+func populateDatasetEncryption(model *poolDatasetResourceModel, result *poolDatasetResult) {
+	root := result.EncryptionRoot != nil && *result.EncryptionRoot == result.Name
+
+	model.Encrypted = types.BoolValue(result.Encrypted)
+	model.Encryption = types.BoolValue(root)
+	model.Locked = types.BoolValue(result.Locked)
+	model.EncryptionKey = types.StringNull()
+	model.EncryptionPassphrase = types.StringNull()
+
+	if !root {
+		model.EncryptionAlgorithm = types.StringNull()
+		model.KeyFormat = types.StringNull()
+		model.EncryptionPbkdf2iters = types.Int64Null()
+		return
+	}
+
+	model.EncryptionAlgorithm = types.StringValue(result.EncryptionAlgorithm.stringValue())
+	model.KeyFormat = types.StringValue(result.KeyFormat.stringValue())
+	model.EncryptionPbkdf2iters = types.Int64Null()
+	if result.KeyFormat.stringValue() == "PASSPHRASE" {
+		if n, ok := result.Pbkdf2iters.int64Value(); ok {
+			model.EncryptionPbkdf2iters = types.Int64Value(n)
+		}
+	}
+}
+
+// lockDataset unloads the key of an encryption root and unmounts it.
+func lockDataset(ctx context.Context, c *client.Client, id string) error {
+	return c.CallJob(ctx, "pool.dataset.lock", []any{id, map[string]any{"force_umount": false}}, nil)
+}
+
+// unlockDataset loads the key of an encryption root, using the write-only passphrase
+// or key of config.
+func unlockDataset(ctx context.Context, c *client.Client, id string, config *poolDatasetResourceModel) error {
+	dataset := map[string]any{"name": id}
+	switch {
+	case !config.EncryptionPassphrase.IsNull():
+		dataset["passphrase"] = config.EncryptionPassphrase.ValueString()
+	case !config.EncryptionKey.IsNull():
+		dataset["key"] = config.EncryptionKey.ValueString()
+	}
+
+	var result struct {
+		Failed map[string]struct {
+			Error string `json:"error"`
+		} `json:"failed"`
+	}
+	options := map[string]any{
+		"key_file":  false,
+		"recursive": false,
+		"datasets":  []any{dataset},
+	}
+	if err := c.CallJob(ctx, "pool.dataset.unlock", []any{id, options}, &result); err != nil {
+		return err
+	}
+	if failed, ok := result.Failed[id]; ok {
+		return fmt.Errorf("dataset %s could not be unlocked: %s", id, failed.Error)
+	}
+	return nil
+}
+
+// planDatasetEncryptionChange marks the computed encryption attributes of an existing
+// dataset unknown when the update changes its key or stops it being an encryption root.
+func planDatasetEncryptionChange(ctx context.Context, plan, state *poolDatasetResourceModel, resp *resource.ModifyPlanResponse) {
+	if state.ID.IsNull() {
+		return
+	}
+
+	inherit := state.Encryption.ValueBool() && !plan.Encryption.IsUnknown() && !plan.Encryption.ValueBool()
+	if !inherit && !datasetKeyChanged(plan, state) {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("key_format"), types.StringUnknown())...)
+	if plan.EncryptionPbkdf2iters.Equal(state.EncryptionPbkdf2iters) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("encryption_pbkdf2iters"), types.Int64Unknown())...)
+	}
+	if inherit {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("encryption_algorithm"), types.StringUnknown())...)
+	}
+}
+
+// datasetKeyChanged reports whether an update of an encryption root must run
+// pool.dataset.change_key.
+func datasetKeyChanged(plan, state *poolDatasetResourceModel) bool {
+	if !state.Encryption.ValueBool() || !plan.Encryption.ValueBool() {
+		return false
+	}
+	return !plan.EncryptionWOVersion.Equal(state.EncryptionWOVersion) ||
+		(!plan.EncryptionPbkdf2iters.IsUnknown() && !plan.EncryptionPbkdf2iters.IsNull() && !plan.EncryptionPbkdf2iters.Equal(state.EncryptionPbkdf2iters))
+}
+
+// changeDatasetKey replaces the key of an encryption root with the key source of config,
+// without re-encrypting its data.
+func changeDatasetKey(ctx context.Context, c *client.Client, id string, plan, config *poolDatasetResourceModel) error {
+	options := map[string]any{
+		"generate_key": plan.EncryptionGenerateKey.ValueBool(),
+		"key_file":     false,
+	}
+	if !config.EncryptionKey.IsNull() {
+		options["key"] = config.EncryptionKey.ValueString()
+	}
+	if !config.EncryptionPassphrase.IsNull() {
+		options["passphrase"] = config.EncryptionPassphrase.ValueString()
+		if !plan.EncryptionPbkdf2iters.IsNull() && !plan.EncryptionPbkdf2iters.IsUnknown() {
+			options["pbkdf2iters"] = plan.EncryptionPbkdf2iters.ValueInt64()
+		}
+	}
+	return c.CallJob(ctx, "pool.dataset.change_key", []any{id, options}, nil)
+}
+
+// inheritDatasetEncryption makes an encryption root inherit the encryption properties,
+// and so the key, of its parent.
+func inheritDatasetEncryption(ctx context.Context, c *client.Client, id string) error {
+	return c.Call(ctx, "pool.dataset.inherit_parent_encryption_properties", []any{id}, nil)
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateDatasetEncryption(t *testing.T) {
+	base := func() poolDatasetResourceModel {
+		return poolDatasetResourceModel{
+			Encryption:            types.BoolValue(true),
+			InheritEncryption:     types.BoolNull(),
+			EncryptionAlgorithm:   types.StringNull(),
+			EncryptionGenerateKey: types.BoolNull(),
+			EncryptionKey:         types.StringNull(),
+			EncryptionPassphrase:  types.StringValue("correct horse"),
+			EncryptionPbkdf2iters: types.Int64Null(),
+			Locked:                types.BoolNull(),
+			CloneFromSnapshot:     types.StringNull(),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(m *poolDatasetResourceModel)
+		wantErr string
+	}{
+		{name: "passphrase", modify: func(m *poolDatasetResourceModel) {
+			m.EncryptionPbkdf2iters = types.Int64Value(350000)
+			m.Locked = types.BoolValue(true)
+		}},
+		{name: "generated key", modify: func(m *poolDatasetResourceModel) {
+			m.EncryptionPassphrase = types.StringNull()
+			m.EncryptionGenerateKey = types.BoolValue(true)
+		}},
+		{name: "unencrypted", modify: func(m *poolDatasetResourceModel) {
+			m.Encryption = types.BoolValue(false)
+			m.EncryptionPassphrase = types.StringNull()
+			m.InheritEncryption = types.BoolValue(false)
+		}},
+		{name: "no key source", modify: func(m *poolDatasetResourceModel) {
+			m.EncryptionPassphrase = types.StringNull()
+		}, wantErr: "Exactly one of"},
+		{name: "two key sources", modify: func(m *poolDatasetResourceModel) {
+			m.EncryptionKey = types.StringValue(strings.Repeat("ab", 32))
+		}, wantErr: "Exactly one of"},
+		{name: "secret without encryption", modify: func(m *poolDatasetResourceModel) {
+			m.Encryption = types.BoolValue(false)
+		}, wantErr: "encryption_passphrase_wo can only be set when encryption is true"},
+		{name: "inherit with encryption", modify: func(m *poolDatasetResourceModel) {
+			m.InheritEncryption = types.BoolValue(true)
+		}, wantErr: "inherit_encryption cannot be true"},
+		{name: "clone", modify: func(m *poolDatasetResourceModel) {
+			m.CloneFromSnapshot = types.StringValue("tank/golden@base")
+		}, wantErr: "clone_from_snapshot"},
+		{name: "iterations with key", modify: func(m *poolDatasetResourceModel) {
+			m.EncryptionPassphrase = types.StringNull()
+			m.EncryptionKey = types.StringValue(strings.Repeat("ab", 32))
+			m.EncryptionPbkdf2iters = types.Int64Value(350000)
+		}, wantErr: "only applies to passphrase encryption"},
+		{name: "lock with key", modify: func(m *poolDatasetResourceModel) {
+			m.EncryptionPassphrase = types.StringNull()
+			m.EncryptionGenerateKey = types.BoolValue(true)
+			m.Locked = types.BoolValue(true)
+		}, wantErr: "Only datasets encrypted with a passphrase can be locked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := base()
+			tt.modify(&config)
+
+			var diags diag.Diagnostics
+			validateDatasetEncryption(&config, &diags)
+
+			if tt.wantErr == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected errors: %v", diags)
+				}
+				return
+			}
+			found := false
+			for _, d := range diags.Errors() {
+				if strings.Contains(d.Detail(), tt.wantErr) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, diags)
+			}
+		})
+	}
+}
+
+func TestDatasetKeyChanged(t *testing.T) {
+	encrypted := func(version, iters int64) *poolDatasetResourceModel {
+		return &poolDatasetResourceModel{
+			Encryption:            types.BoolValue(true),
+			EncryptionWOVersion:   types.Int64Value(version),
+			EncryptionPbkdf2iters: types.Int64Value(iters),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		plan, state *poolDatasetResourceModel
+		want        bool
+	}{
+		{name: "unchanged", plan: encrypted(1, 350000), state: encrypted(1, 350000)},
+		{name: "version bumped", plan: encrypted(2, 350000), state: encrypted(1, 350000), want: true},
+		{name: "iterations changed", plan: encrypted(1, 500000), state: encrypted(1, 350000), want: true},
+		{name: "iterations computed", plan: &poolDatasetResourceModel{
+			Encryption:            types.BoolValue(true),
+			EncryptionWOVersion:   types.Int64Value(1),
+			EncryptionPbkdf2iters: types.Int64Unknown(),
+		}, state: encrypted(1, 350000)},
+		{name: "encryption disabled", plan: &poolDatasetResourceModel{
+			Encryption:          types.BoolValue(false),
+			EncryptionWOVersion: types.Int64Null(),
+		}, state: encrypted(1, 350000)},
+		{name: "not encrypted", plan: &poolDatasetResourceModel{
+			Encryption: types.BoolValue(false),
+		}, state: &poolDatasetResourceModel{
+			Encryption: types.BoolValue(false),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := datasetKeyChanged(tt.plan, tt.state); got != tt.want {
+				t.Errorf("datasetKeyChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = (*datasetParentFunction)(nil)
+
+type datasetParentFunction struct{}
+
+func NewDatasetParentFunction() function.Function {
+	return &datasetParentFunction{}
+}
+
+func (f *datasetParentFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dataset_parent"
+}
+
+func (f *datasetParentFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Return the parent of a dataset.",
+		MarkdownDescription: "Returns the parent dataset of a ZFS dataset path, e.g. `\"tank/apps\"` for `\"tank/apps/db\"`. Fails for a pool root dataset, which has no parent.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "The full dataset path, including the pool.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *datasetParentFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	trimmed := strings.Trim(name, "/")
+	idx := strings.LastIndex(trimmed, "/")
+	if idx <= 0 {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("dataset %q is a pool root and has no parent", name))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, trimmed[:idx]))
+}
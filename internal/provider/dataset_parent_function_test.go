@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestDatasetParentFunction(t *testing.T) {
+	call := func(dataset string) string {
+		return `provider::truenas::dataset_parent("` + dataset + `")`
+	}
+
+	testFunction(t, map[string]functionTestCase{
+		"child":          {expr: call("tank/apps"), want: "tank"},
+		"nested":         {expr: call("tank/apps/db"), want: "tank/apps"},
+		"trailing slash": {expr: call("tank/apps/db/"), want: "tank/apps"},
+		"pool root":      {expr: call("tank"), wantErr: `is a pool root`},
+		"empty":          {expr: call(""), wantErr: `is a pool root`},
+		"null":           {expr: `provider::truenas::dataset_parent(null)`, wantErr: `Invalid function argument`},
+	})
+}
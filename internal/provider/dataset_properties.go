@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// datasetPropertiesModel holds the attributes that truenas_pool_dataset and
+// truenas_pool_zvol share. It is embedded in both models so that the request
+// parameters and the LOCAL-source reads are built in one place.
+type datasetPropertiesModel struct {
+	Comments       types.String `tfsdk:"comments"`
+	Sync           types.String `tfsdk:"sync"`
+	Compression    types.String `tfsdk:"compression"`
+	Readonly       types.String `tfsdk:"readonly"`
+	Deduplication  types.String `tfsdk:"deduplication"`
+	Checksum       types.String `tfsdk:"checksum"`
+	Copies         types.Int64  `tfsdk:"copies"`
+	UserProperties types.Map    `tfsdk:"user_properties"`
+	OwnershipTag   types.String `tfsdk:"ownership_tag"`
+}
+
+// datasetPropertiesResult is the part of a pool.dataset result read into
+// datasetPropertiesModel.
+type datasetPropertiesResult struct {
+	UserProperties poolDatasetUserProperties `json:"user_properties"`
+	Sync           zfsProperty               `json:"sync"`
+	Compression    zfsProperty               `json:"compression"`
+	Readonly       zfsProperty               `json:"readonly"`
+	Deduplication  zfsProperty               `json:"deduplication"`
+	Checksum       zfsProperty               `json:"checksum"`
+	Copies         zfsProperty               `json:"copies"`
+}
+
+// setDatasetPropertiesCreateParams adds the shared properties to pool.dataset.create
+// parameters. Null properties are inherited.
+func setDatasetPropertiesCreateParams(params map[string]any, plan *datasetPropertiesModel) {
+	setStringParam(params, "comments", plan.Comments)
+	setStringParam(params, "sync", plan.Sync)
+	setStringParam(params, "compression", plan.Compression)
+	setStringParam(params, "readonly", plan.Readonly)
+	setStringParam(params, "deduplication", plan.Deduplication)
+	setStringParam(params, "checksum", plan.Checksum)
+	setInt64Param(params, "copies", plan.Copies)
+	if props := userPropertiesCreateParams(userPropertiesMap(plan.UserProperties), plan.OwnershipTag.ValueString()); props != nil {
+		params["user_properties"] = props
+	}
+}
+
+// setDatasetPropertiesUpdateParams adds the shared properties to pool.dataset.update
+// parameters. Properties removed from the configuration revert to inherited values.
+func setDatasetPropertiesUpdateParams(params map[string]any, plan, state *datasetPropertiesModel) {
+	// comments is a ZFS user property — handle explicit set and explicit unset (inherit)
+	if !plan.Comments.IsNull() {
+		params["comments"] = plan.Comments.ValueString()
+	} else if !state.Comments.IsNull() {
+		// Transition from a previously set comment to null: clear/unset so it can inherit
+		params["comments"] = nil
+	}
+	setStringParamOrInherit(params, "sync", plan.Sync)
+	setStringParamOrInherit(params, "compression", plan.Compression)
+	setStringParamOrInherit(params, "readonly", plan.Readonly)
+	setStringParamOrInherit(params, "deduplication", plan.Deduplication)
+	setStringParamOrInherit(params, "checksum", plan.Checksum)
+	// copies accepts int or "INHERIT"
+	if plan.Copies.IsNull() {
+		params["copies"] = "INHERIT"
+	} else {
+		params["copies"] = plan.Copies.ValueInt64()
+	}
+	props := userPropertiesUpdateParams(userPropertiesMap(plan.UserProperties), userPropertiesMap(state.UserProperties))
+	if tag := plan.OwnershipTag.ValueString(); tag != "" && !plan.OwnershipTag.Equal(state.OwnershipTag) {
+		props = append(props, map[string]any{"key": ownershipProperty, "value": tag})
+	}
+	if props != nil {
+		params["user_properties_update"] = props
+	}
+}
+
+// populateDatasetProperties reads the shared properties into the model, storing only
+// LOCAL-sourced values.
+func populateDatasetProperties(model *datasetPropertiesModel, result *datasetPropertiesResult) {
+	model.Comments = readUserProperty(result.UserProperties.Comments)
+	model.UserProperties = readUserProperties(result.UserProperties.Custom)
+	model.OwnershipTag = readUserProperty(result.UserProperties.Workspace)
+	model.Sync = readStringProperty(&result.Sync)
+	model.Compression = readStringProperty(&result.Compression)
+	model.Readonly = readStringProperty(&result.Readonly)
+	model.Deduplication = readStringProperty(&result.Deduplication)
+	model.Checksum = readStringProperty(&result.Checksum)
+	model.Copies = readInt64Property(&result.Copies)
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// userPropertyNamePattern matches a ZFS user property name: a colon-separated
+// namespace of lowercase letters, digits and - _ . : characters.
+var userPropertyNamePattern = regexp.MustCompile(`^[a-z0-9_.-]+:[a-z0-9_.:-]*$`)
+
+// reservedUserPropertyPrefixes are the namespaces of user properties that TrueNAS
+// sets itself. They are never reported in user_properties.
+var reservedUserPropertyPrefixes = []string{"org.freenas:", "org.truenas:"}
+
+type poolDatasetUserProperties struct {
+	Comments  *zfsProperty
+	Workspace *zfsProperty
+	// Custom holds the remaining user properties, keyed by name.
+	Custom map[string]*zfsProperty
+}
+
+// UnmarshalJSON splits the user_properties object of a dataset into the properties
+// managed by dedicated attributes and the rest.
+func (p *poolDatasetUserProperties) UnmarshalJSON(data []byte) error {
+	var all map[string]*zfsProperty
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	p.Comments = all["comments"]
+	p.Workspace = all[ownershipProperty]
+	delete(all, "comments")
+	delete(all, ownershipProperty)
+	p.Custom = all
+	return nil
+}
+
+func isReservedUserProperty(name string) bool {
+	if name == "comments" || name == ownershipProperty {
+		return true
+	}
+	for _, prefix := range reservedUserPropertyPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func userPropertiesAttribute(kind string) schema.MapAttribute {
+	return schema.MapAttribute{
+		Description: "Custom ZFS user properties of the " + kind + ", e.g. {\"com.example:backup\" = \"daily\"}. Names must contain a colon. " +
+			"Only properties set locally on the " + kind + " are stored in state; inherited ones are ignored. Removing a property from the map removes it from the " + kind + ". " +
+			"The comments, " + ownershipProperty + ", org.freenas:* and org.truenas:* properties cannot be set here.",
+		ElementType: types.StringType,
+		Optional:    true,
+		Validators: []validator.Map{
+			mapvalidator.SizeAtLeast(1),
+			mapvalidator.KeysAre(
+				stringvalidator.LengthAtMost(256),
+				stringvalidator.RegexMatches(userPropertyNamePattern, "must be a ZFS user property name: lowercase letters, digits and - _ . : characters, including a colon"),
+				reservedUserPropertyValidator{},
+			),
+		},
+	}
+}
+
+// reservedUserPropertyValidator rejects user property names that are managed by other
+// attributes or by TrueNAS.
+type reservedUserPropertyValidator struct{}
+
+func (v reservedUserPropertyValidator) Description(_ context.Context) string {
+	return "must not be a user property managed by TrueNAS or by another attribute"
+}
+
+func (v reservedUserPropertyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v reservedUserPropertyValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if isReservedUserProperty(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Reserved User Property",
+			"The user property "+req.ConfigValue.ValueString()+" is managed by TrueNAS or by another attribute and cannot be set in user_properties.")
+	}
+}
+
+// readUserProperties returns the LOCAL custom user properties of a dataset as a map,
+// or null when there are none.
+func readUserProperties(props map[string]*zfsProperty) types.Map {
+	values := map[string]attr.Value{}
+	for name, prop := range props {
+		if prop == nil || isReservedUserProperty(name) || !prop.isLocal() {
+			continue
+		}
+		values[name] = types.StringValue(prop.stringValue())
+	}
+	if len(values) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	return types.MapValueMust(types.StringType, values)
+}
+
+// userPropertiesMap returns the elements of a user_properties value.
+func userPropertiesMap(m types.Map) map[string]string {
+	props := map[string]string{}
+	for name, value := range m.Elements() {
+		if v, ok := value.(types.String); ok {
+			props[name] = v.ValueString()
+		}
+	}
+	return props
+}
+
+// userPropertiesCreateParams returns the user_properties creation parameter for the
+// planned custom properties and the ownership tag, or nil when there are none.
+func userPropertiesCreateParams(planned map[string]string, tag string) []map[string]any {
+	var params []map[string]any
+	for _, name := range slices.Sorted(maps.Keys(planned)) {
+		params = append(params, map[string]any{"key": name, "value": planned[name]})
+	}
+	if tag != "" {
+		params = append(params, map[string]any{"key": ownershipProperty, "value": tag})
+	}
+	return params
+}
+
+// userPropertiesUpdateParams returns the user_properties_update parameter that turns
+// the current custom properties into the planned ones: changed properties are set and
+// missing ones removed.
+func userPropertiesUpdateParams(planned, current map[string]string) []map[string]any {
+	var params []map[string]any
+	for _, name := range slices.Sorted(maps.Keys(planned)) {
+		if value, ok := current[name]; !ok || value != planned[name] {
+			params = append(params, map[string]any{"key": name, "value": planned[name]})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		if _, ok := planned[name]; !ok {
+			params = append(params, map[string]any{"key": name, "remove": true})
+		}
+	}
+	return params
+}
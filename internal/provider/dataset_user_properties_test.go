@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestReadUserProperties(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want types.Map
+	}{
+		{
+			name: "local properties",
+			json: `{
+				"com.example:backup": {"value": "daily", "source": "LOCAL"},
+				"com.example:owner": {"value": "ops", "source": "LOCAL"}
+			}`,
+			want: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"com.example:backup": types.StringValue("daily"),
+				"com.example:owner":  types.StringValue("ops"),
+			}),
+		},
+		{
+			name: "inherited properties ignored",
+			json: `{
+				"com.example:backup": {"value": "daily", "source": "INHERITED"},
+				"com.example:owner": {"value": "ops", "source": "LOCAL"}
+			}`,
+			want: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"com.example:owner": types.StringValue("ops"),
+			}),
+		},
+		{
+			name: "managed properties ignored",
+			json: `{
+				"comments": {"value": "hello", "source": "LOCAL"},
+				"org.terraform:workspace": {"value": "prod", "source": "LOCAL"},
+				"org.freenas:description": {"value": "hello", "source": "LOCAL"},
+				"org.truenas:managedby": {"value": "apps", "source": "LOCAL"}
+			}`,
+			want: types.MapNull(types.StringType),
+		},
+		{
+			name: "none",
+			json: `{}`,
+			want: types.MapNull(types.StringType),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var props poolDatasetUserProperties
+			if err := json.Unmarshal([]byte(tt.json), &props); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := readUserProperties(props.Custom); !got.Equal(tt.want) {
+				t.Errorf("readUserProperties() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserPropertiesUpdateParams(t *testing.T) {
+	tests := []struct {
+		name             string
+		planned, current map[string]string
+		want             []map[string]any
+	}{
+		{
+			name:    "unchanged",
+			planned: map[string]string{"com.example:backup": "daily"},
+			current: map[string]string{"com.example:backup": "daily"},
+		},
+		{
+			name:    "added and changed",
+			planned: map[string]string{"com.example:backup": "weekly", "com.example:owner": "ops"},
+			current: map[string]string{"com.example:backup": "daily"},
+			want: []map[string]any{
+				{"key": "com.example:backup", "value": "weekly"},
+				{"key": "com.example:owner", "value": "ops"},
+			},
+		},
+		{
+			name:    "removed",
+			planned: map[string]string{},
+			current: map[string]string{"com.example:backup": "daily", "com.example:owner": "ops"},
+			want: []map[string]any{
+				{"key": "com.example:backup", "remove": true},
+				{"key": "com.example:owner", "remove": true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userPropertiesUpdateParams(tt.planned, tt.current); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("userPropertiesUpdateParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// relativePrefix starts a dataset name or path relative to the provider's
+// default_pool.
+const relativePrefix = "./"
+
+// poolPathKind is the absolute form a relative value resolves to.
+type poolPathKind int
+
+const (
+	datasetNamePath poolPathKind = iota // "./apps" is the dataset "tank/apps"
+	mountPath                           // "./apps" is "/mnt/tank/apps"
+	zvolPath                            // "./vols/lun0" is "zvol/tank/vols/lun0"
+)
+
+func validateDefaultPool(pool string) error {
+	if strings.ContainsAny(pool, "/ ") || strings.HasPrefix(pool, ".") {
+		return fmt.Errorf("default pool %q must be a pool name, without slashes or spaces", pool)
+	}
+	return nil
+}
+
+func isRelativePath(v string) bool {
+	return strings.HasPrefix(v, relativePrefix)
+}
+
+// resolvePoolPath returns the absolute form of v. Values that are not relative, or
+// relative values without a default pool, are returned unchanged; the latter are
+// rejected at plan time by checkRelativePath.
+func resolvePoolPath(defaultPool string, kind poolPathKind, v string) string {
+	if !isRelativePath(v) || defaultPool == "" {
+		return v
+	}
+	rest := strings.TrimLeft(strings.TrimPrefix(v, relativePrefix), "/")
+	name := defaultPool
+	if rest != "" {
+		name += "/" + rest
+	}
+	switch kind {
+	case mountPath:
+		return "/mnt/" + name
+	case zvolPath:
+		return "zvol/" + name
+	default:
+		return name
+	}
+}
+
+// resolvedPath is resolvePoolPath for the value of a string attribute, against the
+// provider's default pool.
+func resolvedPath(d *providerData, kind poolPathKind, v types.String) types.String {
+	if v.IsNull() || v.IsUnknown() {
+		return v
+	}
+	return types.StringValue(resolvePoolPath(providerDefaultPool(d), kind, v.ValueString()))
+}
+
+func providerDefaultPool(d *providerData) string {
+	if d == nil {
+		return ""
+	}
+	return d.defaultPool
+}
+
+// checkRelativePath adds an error to diags if v is relative but the provider has no
+// default_pool. Nothing is checked before the provider is configured.
+func checkRelativePath(d *providerData, p path.Path, v types.String, diags *diag.Diagnostics) {
+	if d == nil || v.IsNull() || v.IsUnknown() || !isRelativePath(v.ValueString()) || d.defaultPool != "" {
+		return
+	}
+	diags.AddAttributeError(
+		p,
+		"Relative Path Without Default Pool",
+		fmt.Sprintf("%q is relative to the provider's default pool, but default_pool is not set. "+
+			"Set default_pool in the provider configuration, TRUENAS_DEFAULT_POOL or the config file profile, or use an absolute value.", v.ValueString()),
+	)
+}
+
+// keepRelativePath returns the configured value prior if it is relative and resolves
+// to actual, the absolute value read from TrueNAS, so that state matches the
+// configuration. Otherwise it returns actual.
+func keepRelativePath(defaultPool string, kind poolPathKind, prior types.String, actual string) types.String {
+	if !prior.IsNull() && !prior.IsUnknown() && isRelativePath(prior.ValueString()) &&
+		resolvePoolPath(defaultPool, kind, prior.ValueString()) == actual {
+		return prior
+	}
+	return types.StringValue(actual)
+}
+
+// resolvedPathAttribute returns the computed attribute holding the absolute form of
+// attr. Terraform requires a configured value to be stored as written, so a relative
+// attr keeps its "./" form and this attribute carries the fully qualified one.
+func resolvedPathAttribute(attr, example string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("The value of %s with a relative path resolved against the provider's default_pool, e.g. %s.", attr, example),
+		Computed:    true,
+	}
+}
+
+// planResolvedPath plans the attribute p created by resolvedPathAttribute from the
+// planned value v. It is unknown while v is, or while a relative v cannot be resolved.
+func planResolvedPath(ctx context.Context, d *providerData, kind poolPathKind, p path.Path, v types.String, resp *resource.ModifyPlanResponse) {
+	resolved := resolvedPath(d, kind, v)
+	if !v.IsNull() && !v.IsUnknown() && isRelativePath(v.ValueString()) && providerDefaultPool(d) == "" {
+		resolved = types.StringUnknown()
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, p, resolved)...)
+}
+
+// ignoreEquivalentReplace drops p from the attributes requiring replacement when the
+// planned and prior values resolve to the same object, e.g. when the configuration
+// switches between the absolute and relative forms of a name.
+func ignoreEquivalentReplace(d *providerData, kind poolPathKind, p path.Path, plan, state types.String, resp *resource.ModifyPlanResponse) {
+	if plan.IsUnknown() || state.IsNull() || !resolvedPath(d, kind, plan).Equal(resolvedPath(d, kind, state)) {
+		return
+	}
+	resp.RequiresReplace = slices.DeleteFunc(resp.RequiresReplace, func(rp path.Path) bool { return rp.Equal(p) })
+}
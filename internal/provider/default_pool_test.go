@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestResolvePoolPath(t *testing.T) {
+	tests := []struct {
+		pool  string
+		kind  poolPathKind
+		value string
+		want  string
+	}{
+		{pool: "tank", kind: datasetNamePath, value: "./apps/db", want: "tank/apps/db"},
+		{pool: "tank", kind: datasetNamePath, value: "./", want: "tank"},
+		{pool: "tank", kind: mountPath, value: "./media", want: "/mnt/tank/media"},
+		{pool: "tank", kind: zvolPath, value: "./vols/lun0", want: "zvol/tank/vols/lun0"},
+		{pool: "tank", kind: datasetNamePath, value: "backup/apps", want: "backup/apps"},
+		{pool: "tank", kind: mountPath, value: "/mnt/backup/media", want: "/mnt/backup/media"},
+		{pool: "", kind: datasetNamePath, value: "./apps", want: "./apps"},
+	}
+
+	for _, tt := range tests {
+		if got := resolvePoolPath(tt.pool, tt.kind, tt.value); got != tt.want {
+			t.Errorf("resolvePoolPath(%q, %d, %q) = %q, want %q", tt.pool, tt.kind, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestKeepRelativePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		pool   string
+		prior  types.String
+		actual string
+		want   types.String
+	}{
+		{name: "relative value kept", pool: "tank", prior: types.StringValue("./apps"), actual: "tank/apps", want: types.StringValue("./apps")},
+		{name: "default pool changed", pool: "tank2", prior: types.StringValue("./apps"), actual: "tank/apps", want: types.StringValue("tank/apps")},
+		{name: "absolute value", pool: "tank", prior: types.StringValue("tank/apps"), actual: "tank/apps", want: types.StringValue("tank/apps")},
+		{name: "import", pool: "tank", prior: types.StringNull(), actual: "tank/apps", want: types.StringValue("tank/apps")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keepRelativePath(tt.pool, datasetNamePath, tt.prior, tt.actual); !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultPool(t *testing.T) {
+	for _, pool := range []string{"", "tank", "boot-pool"} {
+		if err := validateDefaultPool(pool); err != nil {
+			t.Errorf("validateDefaultPool(%q): unexpected error %v", pool, err)
+		}
+	}
+	for _, pool := range []string{"tank/apps", "./tank", "my pool"} {
+		if err := validateDefaultPool(pool); err == nil {
+			t.Errorf("validateDefaultPool(%q): expected an error", pool)
+		}
+	}
+}
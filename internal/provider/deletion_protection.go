@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// deletionProtectionAttribute is the "deletion_protection" attribute of resources that
+// hold data. It defaults to true so that a destroy, or a replacement forced by a change
+// to an immutable attribute, fails until protection is explicitly turned off.
+func deletionProtectionAttribute(kind string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: fmt.Sprintf("Whether Terraform is prevented from deleting the %s, including when a change forces its replacement. "+
+			"Must be set to false, and applied, before the %s can be destroyed. Defaults to true.", kind, kind),
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(true),
+	}
+}
+
+// deletionProtectionValue returns the value to store for deletion_protection. State
+// written before the attribute existed, or created by import, has no value: protection
+// is then enabled, as for new resources.
+func deletionProtectionValue(v types.Bool) types.Bool {
+	if v.IsNull() || v.IsUnknown() {
+		return types.BoolValue(true)
+	}
+	return v
+}
+
+// checkDeletionProtection adds an error to diags if protected is not false. Delete
+// methods call it before deleting anything.
+func checkDeletionProtection(kind, name string, protected types.Bool, diags *diag.Diagnostics) bool {
+	if !deletionProtectionValue(protected).ValueBool() {
+		return true
+	}
+	diags.AddError(
+		"Deletion Protection Enabled",
+		fmt.Sprintf("Cannot delete %s %q because deletion_protection is enabled. "+
+			"Set deletion_protection = false and apply that change before destroying it or making a change that forces its replacement.", kind, name),
+	)
+	return false
+}
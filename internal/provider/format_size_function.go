@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = (*formatSizeFunction)(nil)
+
+type formatSizeFunction struct{}
+
+var formatSizeUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+func NewFormatSizeFunction() function.Function {
+	return &formatSizeFunction{}
+}
+
+func (f *formatSizeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_size"
+}
+
+func (f *formatSizeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Format a number of bytes as a human-readable size.",
+		MarkdownDescription: "Formats a number of bytes using the largest binary unit that keeps the value at or above 1, " +
+			"with at most two decimal places (e.g. `1649267441664` becomes `\"1.5TiB\"`). " +
+			"Exact results round-trip through `parse_size`.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "bytes",
+				Description: "The number of bytes to format.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *formatSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bytes int64
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &bytes))
+	if resp.Error != nil {
+		return
+	}
+
+	if bytes < 0 {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("bytes must not be negative, got %d", bytes))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatSize(bytes)))
+}
+
+// formatSize renders bytes with the largest binary unit that keeps the value >= 1,
+// using at most two decimal places.
+func formatSize(bytes int64) string {
+	unit := 0
+	for unit < len(formatSizeUnits)-1 && bytes >= int64(1)<<(10*(unit+1)) {
+		unit++
+	}
+
+	value := float64(bytes) / float64(int64(1)<<(10*unit))
+	formatted := strconv.FormatFloat(value, 'f', 2, 64)
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+
+	return formatted + formatSizeUnits[unit]
+}
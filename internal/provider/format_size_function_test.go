@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestFormatSizeFunction(t *testing.T) {
+	testFunction(t, map[string]functionTestCase{
+		"zero":       {expr: `provider::truenas::format_size(0)`, want: "0B"},
+		"bytes":      {expr: `provider::truenas::format_size(512)`, want: "512B"},
+		"exact unit": {expr: `provider::truenas::format_size(53687091200)`, want: "50GiB"},
+		"fractional": {expr: `provider::truenas::format_size(1649267441664)`, want: "1.5TiB"},
+		"rounded":    {expr: `provider::truenas::format_size(1234567890)`, want: "1.15GiB"},
+		"exbibytes":  {expr: `provider::truenas::format_size(2305843009213693952)`, want: "2EiB"},
+		"string":     {expr: `provider::truenas::format_size("4096")`, want: "4KiB"},
+		"negative":   {expr: `provider::truenas::format_size(-1)`, wantErr: `bytes must not be negative`},
+		"fraction":   {expr: `provider::truenas::format_size(1.5)`, wantErr: `Invalid function argument`},
+	})
+}
+
+func TestFormatSizeFunction_roundTrip(t *testing.T) {
+	for _, size := range []string{"1KiB", "1.5TiB", "50GiB", "4.25MiB"} {
+		bytes, err := parseSize(size)
+		if err != nil {
+			t.Fatalf("parseSize(%q): %s", size, err)
+		}
+		if got := formatSize(bytes); got != size {
+			t.Errorf("formatSize(parseSize(%q)) = %q", size, got)
+		}
+	}
+}
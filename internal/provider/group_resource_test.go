@@ -27,6 +27,13 @@ func TestAccGroupResource_basic(t *testing.T) {
 				ImportStateVerify:       true,
 				ImportStateVerifyIgnore: []string{"allow_duplicate_gid"},
 			},
+			{
+				ResourceName:            "truenas_group.test",
+				ImportState:             true,
+				ImportStateId:           "tf-acc-test-group",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"allow_duplicate_gid"},
+			},
 		},
 	})
 }
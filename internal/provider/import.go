@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+// importLookup resolves a natural import key (a name, path, NQN, ...) to an object ID.
+type importLookup func(ctx context.Context, c *client.Client, key string) (int64, error)
+
+// resolveImportID returns the object ID for a `terraform import` identifier. Numeric
+// identifiers are used as IDs directly; anything else is resolved with lookup.
+func resolveImportID(ctx context.Context, c *client.Client, importID string, lookup importLookup) (int64, error) {
+	if id, err := strconv.ParseInt(importID, 10, 64); err == nil {
+		return id, nil
+	}
+	if lookup == nil {
+		return 0, fmt.Errorf("%q is not a numeric ID", importID)
+	}
+	if c == nil {
+		return 0, fmt.Errorf("%q is not a numeric ID, and natural keys can only be resolved with a configured provider", importID)
+	}
+	return lookup(ctx, c, importID)
+}
+
+// queryImportID calls a *.query method with filters and returns the ID of the single
+// matching object. kind names the object in errors, e.g. "SMB share".
+func queryImportID(ctx context.Context, c *client.Client, method string, filters []any, kind, key string) (int64, error) {
+	var results []struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.Call(ctx, method, []any{filters}, &results); err != nil {
+		return 0, fmt.Errorf("unable to look up %s %q: %w", kind, key, err)
+	}
+
+	ids := make([]int64, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+	return uniqueImportID(ids, kind, key)
+}
+
+// uniqueImportID returns the only ID in ids, or an error naming the key if there is none
+// or more than one.
+func uniqueImportID(ids []int64, kind, key string) (int64, error) {
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("no %s matches %q", kind, key)
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("%q matches %d %s objects; import by numeric ID instead", key, len(ids), kind)
+	}
+}
+
+// lookupByField returns an importLookup that matches field exactly against the key.
+func lookupByField(method, field, kind string) importLookup {
+	return func(ctx context.Context, c *client.Client, key string) (int64, error) {
+		return queryImportID(ctx, c, method, []any{[]any{field, "=", key}}, kind, key)
+	}
+}
+
+var (
+	lookupUser           = lookupByField("user.query", "username", "user")
+	lookupGroup          = lookupByField("group.query", "group", "group")
+	lookupSMBShare       = lookupByField("sharing.smb.query", "name", "SMB share")
+	lookupNFSShare       = lookupByField("sharing.nfs.query", "path", "NFS share")
+	lookupISCSIExtent    = lookupByField("iscsi.extent.query", "name", "iSCSI extent")
+	lookupNVMeTHost      = lookupByField("nvmet.host.query", "hostnqn", "NVMe-oF host")
+	lookupPool           = lookupByField("pool.query", "name", "pool")
+	lookupPrivilege      = lookupByField("privilege.query", "name", "privilege")
+	lookupAPIKey         = lookupByField("api_key.query", "name", "API key")
+	lookupSnapshotTask   = lookupByField("pool.snapshottask.query", "dataset", "snapshot task")
+	lookupISCSIInitiator = lookupByField("iscsi.initiator.query", "comment", "iSCSI initiator group")
+	lookupNVMeTNamespace = lookupByField("nvmet.namespace.query", "device_path", "NVMe-oF namespace")
+)
+
+// lookupCronjob matches a cron job by "user:command". The command may contain colons;
+// the user name cannot.
+func lookupCronjob(ctx context.Context, c *client.Client, key string) (int64, error) {
+	user, command, ok := strings.Cut(key, ":")
+	if !ok || user == "" || command == "" {
+		return 0, fmt.Errorf("%q is neither a numeric ID nor of the form user:command", key)
+	}
+	filters := []any{
+		[]any{"user", "=", user},
+		[]any{"command", "=", command},
+	}
+	return queryImportID(ctx, c, "cronjob.query", filters, "cron job", key)
+}
+
+// lookupISCSIAuth matches an authorized access by "tag:user", the group tag and the
+// CHAP user name.
+func lookupISCSIAuth(ctx context.Context, c *client.Client, key string) (int64, error) {
+	tagStr, user, ok := strings.Cut(key, ":")
+	tag, err := strconv.ParseInt(tagStr, 10, 64)
+	if !ok || err != nil || user == "" {
+		return 0, fmt.Errorf("%q is neither a numeric ID nor of the form tag:user", key)
+	}
+	filters := []any{
+		[]any{"tag", "=", tag},
+		[]any{"user", "=", user},
+	}
+	return queryImportID(ctx, c, "iscsi.auth.query", filters, "iSCSI authorized access", key)
+}
+
+// lookupNVMeTPort matches a port by its listen address, written as "address" or
+// "address:port" ("[address]:port" for IPv6).
+func lookupNVMeTPort(ctx context.Context, c *client.Client, key string) (int64, error) {
+	addr, port, err := splitImportAddress(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var ports []nvmetPortResult
+	if err := c.Call(ctx, "nvmet.port.query", []any{[]any{[]any{"addr_traddr", "=", addr}}}, &ports); err != nil {
+		return 0, fmt.Errorf("unable to look up NVMe-oF port %q: %w", key, err)
+	}
+
+	var ids []int64
+	for _, p := range ports {
+		if port == 0 || (p.AddrTrsvcid != nil && *p.AddrTrsvcid == port) {
+			ids = append(ids, p.ID)
+		}
+	}
+	return uniqueImportID(ids, "NVMe-oF port", key)
+}
+
+// lookupISCSITarget matches a target by name, or by its full IQN (the global basename,
+// a colon and the name).
+func lookupISCSITarget(ctx context.Context, c *client.Client, key string) (int64, error) {
+	names := []any{key}
+
+	var global struct {
+		Basename string `json:"basename"`
+	}
+	if err := c.Call(ctx, "iscsi.global.config", nil, &global); err != nil {
+		return 0, fmt.Errorf("unable to read iSCSI global configuration: %w", err)
+	}
+	if name, ok := strings.CutPrefix(key, global.Basename+":"); ok && global.Basename != "" {
+		names = append(names, name)
+	}
+
+	return queryImportID(ctx, c, "iscsi.target.query", []any{[]any{"name", "in", names}}, "iSCSI target", key)
+}
+
+// lookupNVMeTSubsys matches a subsystem by NQN or by name.
+func lookupNVMeTSubsys(ctx context.Context, c *client.Client, key string) (int64, error) {
+	filters := []any{
+		[]any{"OR", []any{
+			[]any{"subnqn", "=", key},
+			[]any{"name", "=", key},
+		}},
+	}
+	return queryImportID(ctx, c, "nvmet.subsys.query", filters, "NVMe-oF subsystem", key)
+}
+
+// lookupISCSIPortal matches a portal by one of its listen addresses, written as
+// "address" or "address:port" ("[address]:port" for IPv6). All portals listen on the
+// global iSCSI port, so any other port matches nothing.
+func lookupISCSIPortal(ctx context.Context, c *client.Client, key string) (int64, error) {
+	addr, port, err := splitImportAddress(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if port != 0 {
+		var global struct {
+			ListenPort int64 `json:"listen_port"`
+		}
+		if err := c.Call(ctx, "iscsi.global.config", nil, &global); err != nil {
+			return 0, fmt.Errorf("unable to read iSCSI global configuration: %w", err)
+		}
+		if port != global.ListenPort {
+			return 0, fmt.Errorf("no iSCSI portal matches %q: portals listen on port %d", key, global.ListenPort)
+		}
+	}
+
+	var portals []iscsiPortalResult
+	if err := c.Call(ctx, "iscsi.portal.query", nil, &portals); err != nil {
+		return 0, fmt.Errorf("unable to look up iSCSI portal %q: %w", key, err)
+	}
+
+	var ids []int64
+	for _, portal := range portals {
+		for _, listen := range portal.Listen {
+			if listen.IP == addr {
+				ids = append(ids, portal.ID)
+				break
+			}
+		}
+	}
+	return uniqueImportID(ids, "iSCSI portal", key)
+}
+
+// splitImportAddress splits "address:port" into its parts. A bare address (including an
+// unbracketed IPv6 address) has port 0.
+func splitImportAddress(key string) (string, int64, error) {
+	host, portStr, err := net.SplitHostPort(key)
+	if err != nil {
+		// No port, or an IPv6 address without brackets.
+		return strings.Trim(key, "[]"), 0, nil
+	}
+	port, err := strconv.ParseInt(portStr, 10, 64)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port in %q", key)
+	}
+	return host, port, nil
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestResolveImportIDNumeric(t *testing.T) {
+	id, err := resolveImportID(context.Background(), nil, "42", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if id != 42 {
+		t.Errorf("expected 42, got %d", id)
+	}
+
+	if _, err := resolveImportID(context.Background(), nil, "alice", lookupUser); err == nil {
+		t.Error("expected an error resolving a natural key without a client")
+	}
+}
+
+func TestUniqueImportID(t *testing.T) {
+	if id, err := uniqueImportID([]int64{7}, "group", "staff"); err != nil || id != 7 {
+		t.Errorf("expected 7, got %d (%v)", id, err)
+	}
+
+	_, err := uniqueImportID(nil, "group", "staff")
+	if err == nil || !strings.Contains(err.Error(), `no group matches "staff"`) {
+		t.Errorf("unexpected error for no match: %v", err)
+	}
+
+	_, err = uniqueImportID([]int64{1, 2}, "group", "staff")
+	if err == nil || !strings.Contains(err.Error(), "import by numeric ID") {
+		t.Errorf("unexpected error for several matches: %v", err)
+	}
+}
+
+func TestSplitImportAddress(t *testing.T) {
+	tests := []struct {
+		key     string
+		addr    string
+		port    int64
+		wantErr bool
+	}{
+		{key: "10.0.0.1", addr: "10.0.0.1"},
+		{key: "10.0.0.1:3260", addr: "10.0.0.1", port: 3260},
+		{key: "0.0.0.0:3260", addr: "0.0.0.0", port: 3260},
+		{key: "fe80::1", addr: "fe80::1"},
+		{key: "[fe80::1]", addr: "fe80::1"},
+		{key: "[fe80::1]:3260", addr: "fe80::1", port: 3260},
+		{key: "10.0.0.1:iscsi", wantErr: true},
+		{key: "10.0.0.1:70000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			addr, port, err := splitImportAddress(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %q, %d", addr, port)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if addr != tt.addr || port != tt.port {
+				t.Errorf("expected %q, %d, got %q, %d", tt.addr, tt.port, addr, port)
+			}
+		})
+	}
+}
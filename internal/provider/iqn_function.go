@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = (*iqnFunction)(nil)
+
+type iqnFunction struct{}
+
+var (
+	iqnBasenameRegexp   = regexp.MustCompile(`^iqn\.\d{4}-\d{2}\.[a-z0-9][a-z0-9.-]*(:[a-z0-9.:-]+)?$`)
+	iqnTargetNameRegexp = regexp.MustCompile(`^[a-z0-9.:-]+$`)
+)
+
+func NewIQNFunction() function.Function {
+	return &iqnFunction{}
+}
+
+func (f *iqnFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "iqn"
+}
+
+func (f *iqnFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build the full iSCSI qualified name of a target.",
+		MarkdownDescription: "Builds the IQN that TrueNAS exposes for an `iscsi_target`, i.e. `<basename>:<name>`. " +
+			"As in TrueNAS, a name that is already a full `iqn.`, `eui.` or `naa.` identifier is returned unchanged.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "basename",
+				Description: "The global iSCSI base name (e.g. iqn.2005-10.org.freenas.ctl).",
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "The target name, as set on truenas_iscsi_target.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *iqnFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var basename, name string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &basename, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	if !iqnTargetNameRegexp.MatchString(name) {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf(
+			"invalid target name %q: only lowercase alphanumeric characters, '.', '-' and ':' are allowed", name))
+		return
+	}
+
+	if strings.HasPrefix(name, "iqn.") || strings.HasPrefix(name, "eui.") || strings.HasPrefix(name, "naa.") {
+		resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, name))
+		return
+	}
+
+	if !iqnBasenameRegexp.MatchString(basename) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf(
+			"invalid basename %q: expected the form iqn.YYYY-MM.reversed.domain", basename))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, basename+":"+name))
+}
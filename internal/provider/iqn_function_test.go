@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestIQNFunction(t *testing.T) {
+	call := func(basename, name string) string {
+		return `provider::truenas::iqn("` + basename + `", "` + name + `")`
+	}
+
+	testFunction(t, map[string]functionTestCase{
+		"default basename": {expr: call("iqn.2005-10.org.freenas.ctl", "lun0"), want: "iqn.2005-10.org.freenas.ctl:lun0"},
+		"dotted name":      {expr: call("iqn.2024-01.com.example", "vm.disk-1"), want: "iqn.2024-01.com.example:vm.disk-1"},
+		"full iqn name":    {expr: call("iqn.2005-10.org.freenas.ctl", "iqn.2020-01.com.other:t1"), want: "iqn.2020-01.com.other:t1"},
+		"eui name":         {expr: call("", "eui.0123456789abcdef"), want: "eui.0123456789abcdef"},
+		"uppercase name":   {expr: call("iqn.2005-10.org.freenas.ctl", "LUN0"), wantErr: `invalid target name`},
+		"name with space":  {expr: call("iqn.2005-10.org.freenas.ctl", "lun 0"), wantErr: `invalid target name`},
+		"bad basename":     {expr: call("org.freenas.ctl", "lun0"), wantErr: `invalid basename`},
+		"missing argument": {expr: `provider::truenas::iqn("lun0")`, wantErr: `Not enough function arguments`},
+	})
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -15,39 +16,47 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/barodeur/terraform-provider-truenas/internal/client"
 )
 
 var (
-	_ resource.Resource                = (*iscsiExtentResource)(nil)
-	_ resource.ResourceWithConfigure   = (*iscsiExtentResource)(nil)
-	_ resource.ResourceWithImportState = (*iscsiExtentResource)(nil)
+	_ resource.Resource                 = (*iscsiExtentResource)(nil)
+	_ resource.ResourceWithConfigure    = (*iscsiExtentResource)(nil)
+	_ resource.ResourceWithImportState  = (*iscsiExtentResource)(nil)
+	_ resource.ResourceWithModifyPlan   = (*iscsiExtentResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*iscsiExtentResource)(nil)
 )
 
 type iscsiExtentResource struct {
-	client *client.Client
+	client   *client.Client
+	provider *providerData
 }
 
 type iscsiExtentResourceModel struct {
-	ID             types.Int64  `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	Type           types.String `tfsdk:"type"`
-	Disk           types.String `tfsdk:"disk"`
-	Path           types.String `tfsdk:"path"`
-	Serial         types.String `tfsdk:"serial"`
-	Filesize       types.Int64  `tfsdk:"filesize"`
-	Blocksize      types.Int64  `tfsdk:"blocksize"`
-	Pblocksize     types.Bool   `tfsdk:"pblocksize"`
-	AvailThreshold types.Int64  `tfsdk:"avail_threshold"`
-	Comment        types.String `tfsdk:"comment"`
-	InsecureTPC    types.Bool   `tfsdk:"insecure_tpc"`
-	Xen            types.Bool   `tfsdk:"xen"`
-	RPM            types.String `tfsdk:"rpm"`
-	RO             types.Bool   `tfsdk:"ro"`
-	Enabled        types.Bool   `tfsdk:"enabled"`
-	NAA            types.String `tfsdk:"naa"`
+	ID             types.Int64    `tfsdk:"id"`
+	Name           types.String   `tfsdk:"name"`
+	Type           types.String   `tfsdk:"type"`
+	Disk           types.String   `tfsdk:"disk"`
+	ResolvedDisk   types.String   `tfsdk:"resolved_disk"`
+	Path           types.String   `tfsdk:"path"`
+	ResolvedPath   types.String   `tfsdk:"resolved_path"`
+	Serial         types.String   `tfsdk:"serial"`
+	Filesize       sizeValue      `tfsdk:"filesize"`
+	Blocksize      types.Int64    `tfsdk:"blocksize"`
+	Pblocksize     types.Bool     `tfsdk:"pblocksize"`
+	AvailThreshold types.Int64    `tfsdk:"avail_threshold"`
+	Comment        types.String   `tfsdk:"comment"`
+	InsecureTPC    types.Bool     `tfsdk:"insecure_tpc"`
+	Xen            types.Bool     `tfsdk:"xen"`
+	RPM            types.String   `tfsdk:"rpm"`
+	RO             types.Bool     `tfsdk:"ro"`
+	Enabled        types.Bool     `tfsdk:"enabled"`
+	NAA            types.String   `tfsdk:"naa"`
+	OwnershipTag   types.String   `tfsdk:"ownership_tag"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 type iscsiExtentResult struct {
@@ -78,8 +87,9 @@ func (r *iscsiExtentResource) Metadata(_ context.Context, req resource.MetadataR
 	resp.TypeName = req.ProviderTypeName + "_iscsi_extent"
 }
 
-func (r *iscsiExtentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *iscsiExtentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     1,
 		Description: "Manages a TrueNAS iSCSI extent (storage unit).",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -97,18 +107,23 @@ func (r *iscsiExtentResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "The extent type: DISK or FILE.",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("DISK", "FILE"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"disk": schema.StringAttribute{
-				Description: "The zvol path for DISK type extents (e.g. zvol/tank/iscsi/lun0).",
+				Description: "The zvol path for DISK type extents (e.g. zvol/tank/iscsi/lun0), or a zvol relative to the provider's default_pool (e.g. ./iscsi/lun0). Must be one of the zvols offered by the server when set or changed.",
 				Optional:    true,
 			},
+			"resolved_disk": resolvedPathAttribute("disk", "zvol/tank/iscsi/lun0"),
 			"path": schema.StringAttribute{
-				Description: "The file path for FILE type extents.",
+				Description: "The file path for FILE type extents, absolute or relative to the provider's default_pool (e.g. ./iscsi/lun0.img).",
 				Optional:    true,
 			},
+			"resolved_path": resolvedPathAttribute("path", "/mnt/tank/iscsi/lun0.img"),
 			"serial": schema.StringAttribute{
 				Description: "Serial number for the extent. Auto-generated if not specified.",
 				Optional:    true,
@@ -117,14 +132,18 @@ func (r *iscsiExtentResource) Schema(_ context.Context, _ resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"filesize": schema.Int64Attribute{
-				Description: "Size of the file extent in bytes (only for FILE type).",
+			"filesize": schema.StringAttribute{
+				Description: "Size of the file extent (only for FILE type), as bytes or with a unit such as \"50GiB\".",
 				Optional:    true,
+				CustomType:  sizeType{},
 			},
 			"blocksize": schema.Int64Attribute{
 				Description: "Logical block size (512, 1024, 2048, or 4096).",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.Int64{
+					int64validator.OneOf(512, 1024, 2048, 4096),
+				},
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
 				},
@@ -140,6 +159,9 @@ func (r *iscsiExtentResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"avail_threshold": schema.Int64Attribute{
 				Description: "Pool available space threshold percentage (1-99) for warnings.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 99),
+				},
 			},
 			"comment": schema.StringAttribute{
 				Description: "Description of the extent.",
@@ -165,6 +187,9 @@ func (r *iscsiExtentResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "RPM speed reported to initiators (SSD, UNKNOWN, 5400, 7200, 10000, 15000).",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("UNKNOWN", "SSD", "5400", "7200", "10000", "15000"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -190,6 +215,8 @@ func (r *iscsiExtentResource) Schema(_ context.Context, _ resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"ownership_tag": ownershipTagAttribute("extent", "at the end of its comment, as [terraform:<tag>]"),
+			"timeouts":      timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
@@ -199,16 +226,48 @@ func (r *iscsiExtentResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
+	r.provider = data
+}
+
+// ModifyPlan checks that a newly configured disk is a zvol TrueNAS can export. Zvols
+// already backing an extent are not offered by iscsi.extent.disk_choices, so the check
+// only runs when the disk changes.
+// It also plans the ownership tag.
+func (r *iscsiExtentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state iscsiExtentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkRelativePath(r.provider, path.Root("disk"), plan.Disk, &resp.Diagnostics)
+	checkRelativePath(r.provider, path.Root("path"), plan.Path, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planResolvedPath(ctx, r.provider, zvolPath, path.Root("resolved_disk"), plan.Disk, resp)
+	planResolvedPath(ctx, r.provider, mountPath, path.Root("resolved_path"), plan.Path, resp)
+	planOwnershipTag(ctx, r.provider, req, resp)
+
+	validateServerChoice(ctx, r.client, "iscsi.extent.disk_choices", []any{}, path.Root("disk"),
+		resolvedPath(r.provider, zvolPath, plan.Disk), resolvedPath(r.provider, zvolPath, state.Disk), &resp.Diagnostics)
 }
 
 func (r *iscsiExtentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -218,6 +277,15 @@ func (r *iscsiExtentResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	params := map[string]any{
 		"name":    plan.Name.ValueString(),
 		"enabled": plan.Enabled.ValueBool(),
@@ -227,16 +295,16 @@ func (r *iscsiExtentResource) Create(ctx context.Context, req resource.CreateReq
 		params["type"] = plan.Type.ValueString()
 	}
 	if !plan.Disk.IsNull() && !plan.Disk.IsUnknown() {
-		params["disk"] = plan.Disk.ValueString()
+		params["disk"] = resolvePoolPath(r.provider.defaultPool, zvolPath, plan.Disk.ValueString())
 	}
 	if !plan.Path.IsNull() && !plan.Path.IsUnknown() {
-		params["path"] = plan.Path.ValueString()
+		params["path"] = resolvePoolPath(r.provider.defaultPool, mountPath, plan.Path.ValueString())
 	}
 	if !plan.Serial.IsNull() && !plan.Serial.IsUnknown() {
 		params["serial"] = plan.Serial.ValueString()
 	}
 	if !plan.Filesize.IsNull() && !plan.Filesize.IsUnknown() {
-		params["filesize"] = plan.Filesize.ValueInt64()
+		params["filesize"] = plan.Filesize.ValueBytes()
 	}
 	if !plan.Blocksize.IsNull() && !plan.Blocksize.IsUnknown() {
 		params["blocksize"] = plan.Blocksize.ValueInt64()
@@ -247,8 +315,8 @@ func (r *iscsiExtentResource) Create(ctx context.Context, req resource.CreateReq
 	if !plan.AvailThreshold.IsNull() && !plan.AvailThreshold.IsUnknown() {
 		params["avail_threshold"] = plan.AvailThreshold.ValueInt64()
 	}
-	if !plan.Comment.IsNull() && !plan.Comment.IsUnknown() {
-		params["comment"] = plan.Comment.ValueString()
+	if comment := withOwnershipMarker(plan.Comment.ValueString(), plan.OwnershipTag.ValueString()); comment != "" {
+		params["comment"] = comment
 	}
 	if !plan.InsecureTPC.IsNull() && !plan.InsecureTPC.IsUnknown() {
 		params["insecure_tpc"] = plan.InsecureTPC.ValueBool()
@@ -270,7 +338,7 @@ func (r *iscsiExtentResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	populateISCSIExtentState(&plan, &result)
+	populateISCSIExtentState(&plan, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -284,7 +352,7 @@ func (r *iscsiExtentResource) Read(ctx context.Context, req resource.ReadRequest
 	var result iscsiExtentResult
 	err := r.client.Call(ctx, "iscsi.extent.get_instance", []any{state.ID.ValueInt64()}, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -292,7 +360,7 @@ func (r *iscsiExtentResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	populateISCSIExtentState(&state, &result)
+	populateISCSIExtentState(&state, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -303,6 +371,15 @@ func (r *iscsiExtentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var state iscsiExtentResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -318,12 +395,12 @@ func (r *iscsiExtentResource) Update(ctx context.Context, req resource.UpdateReq
 		params["type"] = plan.Type.ValueString()
 	}
 	if !plan.Disk.IsNull() && !plan.Disk.IsUnknown() {
-		params["disk"] = plan.Disk.ValueString()
+		params["disk"] = resolvePoolPath(r.provider.defaultPool, zvolPath, plan.Disk.ValueString())
 	} else if plan.Disk.IsNull() {
 		params["disk"] = ""
 	}
 	if !plan.Path.IsNull() && !plan.Path.IsUnknown() {
-		params["path"] = plan.Path.ValueString()
+		params["path"] = resolvePoolPath(r.provider.defaultPool, mountPath, plan.Path.ValueString())
 	} else if plan.Path.IsNull() {
 		params["path"] = ""
 	}
@@ -331,7 +408,7 @@ func (r *iscsiExtentResource) Update(ctx context.Context, req resource.UpdateReq
 		params["serial"] = plan.Serial.ValueString()
 	}
 	if !plan.Filesize.IsNull() && !plan.Filesize.IsUnknown() {
-		params["filesize"] = plan.Filesize.ValueInt64()
+		params["filesize"] = plan.Filesize.ValueBytes()
 	}
 	if !plan.Blocksize.IsNull() && !plan.Blocksize.IsUnknown() {
 		params["blocksize"] = plan.Blocksize.ValueInt64()
@@ -342,11 +419,7 @@ func (r *iscsiExtentResource) Update(ctx context.Context, req resource.UpdateReq
 	if !plan.AvailThreshold.IsNull() {
 		params["avail_threshold"] = plan.AvailThreshold.ValueInt64()
 	}
-	if !plan.Comment.IsNull() {
-		params["comment"] = plan.Comment.ValueString()
-	} else {
-		params["comment"] = ""
-	}
+	params["comment"] = withOwnershipMarker(plan.Comment.ValueString(), plan.OwnershipTag.ValueString())
 	if !plan.InsecureTPC.IsNull() && !plan.InsecureTPC.IsUnknown() {
 		params["insecure_tpc"] = plan.InsecureTPC.ValueBool()
 	}
@@ -367,7 +440,7 @@ func (r *iscsiExtentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	populateISCSIExtentState(&plan, &result)
+	populateISCSIExtentState(&plan, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -378,6 +451,15 @@ func (r *iscsiExtentResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Pass false, false to never remove underlying storage
 	err := r.client.Call(ctx, "iscsi.extent.delete", []any{state.ID.ValueInt64(), false, false}, nil)
 	if err != nil {
@@ -387,19 +469,38 @@ func (r *iscsiExtentResource) Delete(ctx context.Context, req resource.DeleteReq
 }
 
 func (r *iscsiExtentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
+	id, err := resolveImportID(ctx, r.client, req.ID, lookupISCSIExtent)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Importing iSCSI Extent",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
+			fmt.Sprintf("Could not resolve import ID %q: %s", req.ID, err.Error()),
 		)
 		return
 	}
 
+	// The ownership check reads the object, which is skipped before the provider is
+	// configured.
+	if r.client != nil {
+		var result iscsiExtentResult
+		if err := r.client.Call(ctx, "iscsi.extent.get_instance", []any{id}, &result); err == nil {
+			_, tag := splitOwnershipMarker(result.Comment)
+			checkImportOwnership(r.provider, "iSCSI Extent", result.Name, tag, &resp.Diagnostics)
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
-func populateISCSIExtentState(model *iscsiExtentResourceModel, result *iscsiExtentResult) {
+// UpgradeState migrates state written by earlier schema versions.
+//
+// Version 1 changed filesize from a number to a string accepting units.
+func (r *iscsiExtentResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return stateUpgraders(
+		numbersToStrings("filesize"),
+	)
+}
+
+func populateISCSIExtentState(model *iscsiExtentResourceModel, result *iscsiExtentResult, defaultPool string) {
 	model.ID = types.Int64Value(result.ID)
 	model.Name = types.StringValue(result.Name)
 	model.Type = types.StringValue(result.Type)
@@ -414,15 +515,19 @@ func populateISCSIExtentState(model *iscsiExtentResourceModel, result *iscsiExte
 	model.RO = types.BoolValue(result.RO)
 
 	if result.Disk != "" {
-		model.Disk = types.StringValue(result.Disk)
+		model.Disk = keepRelativePath(defaultPool, zvolPath, model.Disk, result.Disk)
+		model.ResolvedDisk = types.StringValue(result.Disk)
 	} else {
 		model.Disk = types.StringNull()
+		model.ResolvedDisk = types.StringNull()
 	}
 
 	if result.Path != "" {
-		model.Path = types.StringValue(result.Path)
+		model.Path = keepRelativePath(defaultPool, mountPath, model.Path, result.Path)
+		model.ResolvedPath = types.StringValue(result.Path)
 	} else {
 		model.Path = types.StringNull()
+		model.ResolvedPath = types.StringNull()
 	}
 
 	if result.AvailThreshold != nil {
@@ -431,15 +536,21 @@ func populateISCSIExtentState(model *iscsiExtentResourceModel, result *iscsiExte
 		model.AvailThreshold = types.Int64Null()
 	}
 
-	if result.Comment != "" {
-		model.Comment = types.StringValue(result.Comment)
+	comment, tag := splitOwnershipMarker(result.Comment)
+	if comment != "" {
+		model.Comment = types.StringValue(comment)
 	} else {
 		model.Comment = types.StringNull()
 	}
+	if tag != "" {
+		model.OwnershipTag = types.StringValue(tag)
+	} else {
+		model.OwnershipTag = types.StringNull()
+	}
 
 	if fs, err := result.Filesize.Int64(); err == nil && fs != 0 {
-		model.Filesize = types.Int64Value(fs)
+		model.Filesize = sizeBytes(fs)
 	} else {
-		model.Filesize = types.Int64Null()
+		model.Filesize = sizeNull()
 	}
 }
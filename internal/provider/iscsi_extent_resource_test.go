@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -57,6 +58,27 @@ func TestAccISCSIExtentResource_update(t *testing.T) {
 	})
 }
 
+func TestAccISCSIExtentResource_invalidValues(t *testing.T) {
+	pool := testAccPoolName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccISCSIExtentResourceConfigWithAttr(pool, "blocksize", "3000"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Attribute blocksize value must be one of`),
+			},
+			{
+				Config:      testAccISCSIExtentResourceConfigWithAttr(pool, "rpm", `"9000"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Attribute rpm value must be one of`),
+			},
+		},
+	})
+}
+
 func testAccISCSIExtentResourceConfig(pool string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_iscsi_extent" "test" {
@@ -79,3 +101,15 @@ resource "truenas_iscsi_extent" "test" {
 }
 `, pool)
 }
+
+func testAccISCSIExtentResourceConfigWithAttr(pool, attr, value string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_iscsi_extent" "test" {
+  name     = "tf-acc-test-extent"
+  type     = "FILE"
+  path     = "/mnt/%s/iscsi-test-extent"
+  filesize = 10485760
+  %s = %s
+}
+`, pool, attr, value)
+}
@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -12,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/barodeur/terraform-provider-truenas/internal/client"
@@ -28,12 +31,13 @@ type iscsiGlobalResource struct {
 }
 
 type iscsiGlobalResourceModel struct {
-	ID                 types.Int64  `tfsdk:"id"`
-	Basename           types.String `tfsdk:"basename"`
-	ISNSServers        types.List   `tfsdk:"isns_servers"`
-	ListenPort         types.Int64  `tfsdk:"listen_port"`
-	PoolAvailThreshold types.Int64  `tfsdk:"pool_avail_threshold"`
-	ALUA               types.Bool   `tfsdk:"alua"`
+	ID                 types.Int64    `tfsdk:"id"`
+	Basename           types.String   `tfsdk:"basename"`
+	ISNSServers        types.List     `tfsdk:"isns_servers"`
+	ListenPort         types.Int64    `tfsdk:"listen_port"`
+	PoolAvailThreshold types.Int64    `tfsdk:"pool_avail_threshold"`
+	ALUA               types.Bool     `tfsdk:"alua"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type iscsiGlobalResult struct {
@@ -53,7 +57,7 @@ func (r *iscsiGlobalResource) Metadata(_ context.Context, req resource.MetadataR
 	resp.TypeName = req.ProviderTypeName + "_iscsi_global"
 }
 
-func (r *iscsiGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *iscsiGlobalResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages the TrueNAS global iSCSI configuration. This is a singleton resource.\n\n" +
 			"~> **Note:** This is a singleton resource — only one instance exists per TrueNAS system. " +
@@ -69,6 +73,9 @@ func (r *iscsiGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"basename": schema.StringAttribute{
 				Description: "The base name for iSCSI targets (e.g. iqn.2005-10.org.freenas.ctl).",
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(iqnBasenameRegexp, "must be of the form iqn.YYYY-MM.reversed.domain"),
+				},
 			},
 			"isns_servers": schema.ListAttribute{
 				Description: "List of iSNS server addresses.",
@@ -79,6 +86,9 @@ func (r *iscsiGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "The TCP port iSCSI listens on. Defaults to 3260.",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
 				},
@@ -86,6 +96,9 @@ func (r *iscsiGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"pool_avail_threshold": schema.Int64Attribute{
 				Description: "Pool available space threshold percentage for alerts.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 99),
+				},
 			},
 			"alua": schema.BoolAttribute{
 				Description: "Enable Asymmetric Logical Unit Access.",
@@ -95,6 +108,7 @@ func (r *iscsiGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest
 					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true}),
 		},
 	}
 }
@@ -104,16 +118,16 @@ func (r *iscsiGlobalResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
 }
 
 func (r *iscsiGlobalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -123,6 +137,15 @@ func (r *iscsiGlobalResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	params := iscsiGlobalParamsFromModel(ctx, &plan, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -149,7 +172,7 @@ func (r *iscsiGlobalResource) Read(ctx context.Context, req resource.ReadRequest
 	var result iscsiGlobalResult
 	err := r.client.Call(ctx, "iscsi.global.config", nil, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -168,6 +191,15 @@ func (r *iscsiGlobalResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	params := iscsiGlobalParamsFromModel(ctx, &plan, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -2,36 +2,22 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
-)
-
-var (
-	_ resource.Resource                = (*iscsiInitiatorResource)(nil)
-	_ resource.ResourceWithConfigure   = (*iscsiInitiatorResource)(nil)
-	_ resource.ResourceWithImportState = (*iscsiInitiatorResource)(nil)
 )
 
-type iscsiInitiatorResource struct {
-	client *client.Client
-}
-
 type iscsiInitiatorResourceModel struct {
-	ID         types.Int64  `tfsdk:"id"`
-	Initiators types.List   `tfsdk:"initiators"`
-	Comment    types.String `tfsdk:"comment"`
+	ID         types.Int64    `tfsdk:"id"`
+	Initiators types.List     `tfsdk:"initiators"`
+	Comment    types.String   `tfsdk:"comment"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 type iscsiInitiatorResult struct {
@@ -41,15 +27,19 @@ type iscsiInitiatorResult struct {
 }
 
 func NewISCSIInitiatorResource() resource.Resource {
-	return &iscsiInitiatorResource{}
-}
-
-func (r *iscsiInitiatorResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_iscsi_initiator"
+	return newCRUDResource(crudSpec[iscsiInitiatorResourceModel, iscsiInitiatorResult]{
+		typeName:     "iscsi_initiator",
+		namespace:    "iscsi.initiator",
+		kind:         "iSCSI Initiator",
+		schema:       iscsiInitiatorSchema,
+		params:       iscsiInitiatorParams,
+		populate:     populateISCSIInitiatorState,
+		importLookup: lookupISCSIInitiator,
+	})
 }
 
-func (r *iscsiInitiatorResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func iscsiInitiatorSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		Description: "Manages a TrueNAS iSCSI authorized initiator group.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -68,150 +58,29 @@ func (r *iscsiInitiatorResource) Schema(_ context.Context, _ resource.SchemaRequ
 				Description: "Description of the initiator group.",
 				Optional:    true,
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *iscsiInitiatorResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
-	}
-
-	r.client = c
-}
-
-func (r *iscsiInitiatorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan iscsiInitiatorResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := map[string]any{}
-
-	if !plan.Initiators.IsNull() && !plan.Initiators.IsUnknown() {
-		var initiators []string
-		resp.Diagnostics.Append(plan.Initiators.ElementsAs(ctx, &initiators, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		params["initiators"] = initiators
-	}
-
-	if !plan.Comment.IsNull() && !plan.Comment.IsUnknown() {
-		params["comment"] = plan.Comment.ValueString()
-	}
-
-	var result iscsiInitiatorResult
-	err := r.client.Call(ctx, "iscsi.initiator.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Initiator", err.Error())
-		return
-	}
-
-	populateISCSIInitiatorState(ctx, &plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *iscsiInitiatorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state iscsiInitiatorResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var result iscsiInitiatorResult
-	err := r.client.Call(ctx, "iscsi.initiator.get_instance", []any{state.ID.ValueInt64()}, &result)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
-			resp.State.RemoveResource(ctx)
-			return
-		}
-		resp.Diagnostics.AddError("Error Reading iSCSI Initiator", err.Error())
-		return
-	}
-
-	populateISCSIInitiatorState(ctx, &state, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-}
-
-func (r *iscsiInitiatorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan iscsiInitiatorResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var state iscsiInitiatorResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
+func iscsiInitiatorParams(ctx context.Context, plan, state *iscsiInitiatorResourceModel, diags *diag.Diagnostics) map[string]any {
 	params := map[string]any{}
 
 	if !plan.Initiators.IsNull() && !plan.Initiators.IsUnknown() {
 		var initiators []string
-		resp.Diagnostics.Append(plan.Initiators.ElementsAs(ctx, &initiators, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+		diags.Append(plan.Initiators.ElementsAs(ctx, &initiators, false)...)
 		params["initiators"] = initiators
-	} else if plan.Initiators.IsNull() {
+	} else if plan.Initiators.IsNull() && state != nil {
 		params["initiators"] = []string{}
 	}
 
-	if !plan.Comment.IsNull() {
+	if !plan.Comment.IsNull() && !plan.Comment.IsUnknown() {
 		params["comment"] = plan.Comment.ValueString()
-	} else {
+	} else if state != nil {
 		params["comment"] = ""
 	}
 
-	var result iscsiInitiatorResult
-	err := r.client.Call(ctx, "iscsi.initiator.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating iSCSI Initiator", err.Error())
-		return
-	}
-
-	populateISCSIInitiatorState(ctx, &plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *iscsiInitiatorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state iscsiInitiatorResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	err := r.client.Call(ctx, "iscsi.initiator.delete", []any{state.ID.ValueInt64()}, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting iSCSI Initiator", err.Error())
-		return
-	}
-}
-
-func (r *iscsiInitiatorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing iSCSI Initiator",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	return params
 }
 
 func populateISCSIInitiatorState(_ context.Context, model *iscsiInitiatorResourceModel, result *iscsiInitiatorResult, diags *diag.Diagnostics) {
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -74,16 +73,16 @@ func (d *iscsiPortalDataSource) Configure(_ context.Context, req datasource.Conf
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = c
+	d.client = data.client
 }
 
 func (d *iscsiPortalDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -96,7 +95,7 @@ func (d *iscsiPortalDataSource) Read(ctx context.Context, req datasource.ReadReq
 	var result iscsiPortalResult
 	err := d.client.Call(ctx, "iscsi.portal.get_instance", []any{config.ID.ValueInt64()}, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.Diagnostics.AddError(
 				"iSCSI Portal Not Found",
 				fmt.Sprintf("No iSCSI portal with ID %d was found.", config.ID.ValueInt64()),
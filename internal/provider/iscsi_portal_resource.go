@@ -2,37 +2,23 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
-)
-
-var (
-	_ resource.Resource                = (*iscsiPortalResource)(nil)
-	_ resource.ResourceWithConfigure   = (*iscsiPortalResource)(nil)
-	_ resource.ResourceWithImportState = (*iscsiPortalResource)(nil)
 )
 
-type iscsiPortalResource struct {
-	client *client.Client
-}
-
 type iscsiPortalResourceModel struct {
-	ID      types.Int64  `tfsdk:"id"`
-	Listen  types.List   `tfsdk:"listen"`
-	Comment types.String `tfsdk:"comment"`
-	Tag     types.Int64  `tfsdk:"tag"`
+	ID       types.Int64    `tfsdk:"id"`
+	Listen   types.List     `tfsdk:"listen"`
+	Comment  types.String   `tfsdk:"comment"`
+	Tag      types.Int64    `tfsdk:"tag"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 var iscsiPortalListenAttrTypes = map[string]attr.Type{
@@ -51,15 +37,19 @@ type iscsiPortalResult struct {
 }
 
 func NewISCSIPortalResource() resource.Resource {
-	return &iscsiPortalResource{}
-}
-
-func (r *iscsiPortalResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_iscsi_portal"
-}
-
-func (r *iscsiPortalResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+	return newCRUDResource(crudSpec[iscsiPortalResourceModel, iscsiPortalResult]{
+		typeName:     "iscsi_portal",
+		namespace:    "iscsi.portal",
+		kind:         "iSCSI Portal",
+		schema:       iscsiPortalSchema,
+		params:       iscsiPortalParams,
+		populate:     populateISCSIPortalState,
+		importLookup: lookupISCSIPortal,
+	})
+}
+
+func iscsiPortalSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		Description: "Manages a TrueNAS iSCSI portal.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -92,142 +82,23 @@ func (r *iscsiPortalResource) Schema(_ context.Context, _ resource.SchemaRequest
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *iscsiPortalResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
+func iscsiPortalParams(ctx context.Context, plan, state *iscsiPortalResourceModel, diags *diag.Diagnostics) map[string]any {
+	params := map[string]any{
+		"listen": iscsiPortalListenFromPlan(ctx, plan.Listen, diags),
 	}
 
-	r.client = c
-}
-
-func (r *iscsiPortalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan iscsiPortalResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := map[string]any{}
-
-	listen := iscsiPortalListenFromPlan(ctx, plan.Listen, &resp.Diagnostics)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	params["listen"] = listen
-
 	if !plan.Comment.IsNull() && !plan.Comment.IsUnknown() {
 		params["comment"] = plan.Comment.ValueString()
-	}
-
-	var result iscsiPortalResult
-	err := r.client.Call(ctx, "iscsi.portal.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Portal", err.Error())
-		return
-	}
-
-	populateISCSIPortalState(&plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *iscsiPortalResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state iscsiPortalResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var result iscsiPortalResult
-	err := r.client.Call(ctx, "iscsi.portal.get_instance", []any{state.ID.ValueInt64()}, &result)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
-			resp.State.RemoveResource(ctx)
-			return
-		}
-		resp.Diagnostics.AddError("Error Reading iSCSI Portal", err.Error())
-		return
-	}
-
-	populateISCSIPortalState(&state, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-}
-
-func (r *iscsiPortalResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan iscsiPortalResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var state iscsiPortalResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := map[string]any{}
-
-	listen := iscsiPortalListenFromPlan(ctx, plan.Listen, &resp.Diagnostics)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	params["listen"] = listen
-
-	if !plan.Comment.IsNull() {
-		params["comment"] = plan.Comment.ValueString()
-	} else {
+	} else if state != nil {
 		params["comment"] = ""
 	}
 
-	var result iscsiPortalResult
-	err := r.client.Call(ctx, "iscsi.portal.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating iSCSI Portal", err.Error())
-		return
-	}
-
-	populateISCSIPortalState(&plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *iscsiPortalResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state iscsiPortalResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	err := r.client.Call(ctx, "iscsi.portal.delete", []any{state.ID.ValueInt64()}, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting iSCSI Portal", err.Error())
-		return
-	}
-}
-
-func (r *iscsiPortalResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing iSCSI Portal",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	return params
 }
 
 func iscsiPortalListenFromPlan(ctx context.Context, listVal types.List, diags *diag.Diagnostics) []map[string]any {
@@ -245,7 +116,7 @@ func iscsiPortalListenFromPlan(ctx context.Context, listVal types.List, diags *d
 	return result
 }
 
-func populateISCSIPortalState(model *iscsiPortalResourceModel, result *iscsiPortalResult, diags *diag.Diagnostics) {
+func populateISCSIPortalState(_ context.Context, model *iscsiPortalResourceModel, result *iscsiPortalResult, diags *diag.Diagnostics) {
 	model.ID = types.Int64Value(result.ID)
 	model.Tag = types.Int64Value(result.Tag)
 
@@ -2,39 +2,27 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
-)
-
-var (
-	_ resource.Resource                = (*iscsiTargetResource)(nil)
-	_ resource.ResourceWithConfigure   = (*iscsiTargetResource)(nil)
-	_ resource.ResourceWithImportState = (*iscsiTargetResource)(nil)
 )
 
-type iscsiTargetResource struct {
-	client *client.Client
-}
-
 type iscsiTargetResourceModel struct {
-	ID     types.Int64  `tfsdk:"id"`
-	Name   types.String `tfsdk:"name"`
-	Alias  types.String `tfsdk:"alias"`
-	Mode   types.String `tfsdk:"mode"`
-	Groups types.List   `tfsdk:"groups"`
+	ID       types.Int64    `tfsdk:"id"`
+	Name     types.String   `tfsdk:"name"`
+	Alias    types.String   `tfsdk:"alias"`
+	Mode     types.String   `tfsdk:"mode"`
+	Groups   types.List     `tfsdk:"groups"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 var iscsiTargetGroupAttrTypes = map[string]attr.Type{
@@ -60,15 +48,21 @@ type iscsiTargetResult struct {
 }
 
 func NewISCSITargetResource() resource.Resource {
-	return &iscsiTargetResource{}
-}
-
-func (r *iscsiTargetResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_iscsi_target"
-}
-
-func (r *iscsiTargetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+	return newCRUDResource(crudSpec[iscsiTargetResourceModel, iscsiTargetResult]{
+		typeName:     "iscsi_target",
+		namespace:    "iscsi.target",
+		kind:         "iSCSI Target",
+		schema:       iscsiTargetSchema,
+		params:       iscsiTargetParams,
+		populate:     populateISCSITargetState,
+		importLookup: lookupISCSITarget,
+		// No force, no cascade.
+		deleteArgs: func(*iscsiTargetResourceModel) []any { return []any{false, false} },
+	})
+}
+
+func iscsiTargetSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		Description: "Manages a TrueNAS iSCSI target.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -81,6 +75,9 @@ func (r *iscsiTargetResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"name": schema.StringAttribute{
 				Description: "The base name of the target (appended to the global basename).",
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(iqnTargetNameRegexp, "must contain only lowercase alphanumeric characters, '.', '-' and ':'"),
+				},
 			},
 			"alias": schema.StringAttribute{
 				Description: "An optional alias for the target.",
@@ -90,6 +87,9 @@ func (r *iscsiTargetResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "Target mode: ISCSI, FC, or BOTH.",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ISCSI", "FC", "BOTH"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -111,6 +111,9 @@ func (r *iscsiTargetResource) Schema(_ context.Context, _ resource.SchemaRequest
 							Description: "Authentication method: NONE, CHAP, or CHAP_MUTUAL.",
 							Optional:    true,
 							Computed:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("NONE", "CHAP", "CHAP_MUTUAL"),
+							},
 							PlanModifiers: []planmodifier.String{
 								stringplanmodifier.UseStateForUnknown(),
 							},
@@ -122,106 +125,19 @@ func (r *iscsiTargetResource) Schema(_ context.Context, _ resource.SchemaRequest
 					},
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *iscsiTargetResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
-	}
-
-	r.client = c
-}
-
-func (r *iscsiTargetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan iscsiTargetResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := map[string]any{
-		"name": plan.Name.ValueString(),
-	}
-
-	if !plan.Alias.IsNull() && !plan.Alias.IsUnknown() {
-		params["alias"] = plan.Alias.ValueString()
-	}
-	if !plan.Mode.IsNull() && !plan.Mode.IsUnknown() {
-		params["mode"] = plan.Mode.ValueString()
-	}
-
-	if !plan.Groups.IsNull() && !plan.Groups.IsUnknown() {
-		groups := iscsiTargetGroupsFromPlan(ctx, plan.Groups, &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		params["groups"] = groups
-	}
-
-	var result iscsiTargetResult
-	err := r.client.Call(ctx, "iscsi.target.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Target", err.Error())
-		return
-	}
-
-	populateISCSITargetState(&plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *iscsiTargetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state iscsiTargetResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var result iscsiTargetResult
-	err := r.client.Call(ctx, "iscsi.target.get_instance", []any{state.ID.ValueInt64()}, &result)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
-			resp.State.RemoveResource(ctx)
-			return
-		}
-		resp.Diagnostics.AddError("Error Reading iSCSI Target", err.Error())
-		return
-	}
-
-	populateISCSITargetState(&state, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-}
-
-func (r *iscsiTargetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan iscsiTargetResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var state iscsiTargetResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
+func iscsiTargetParams(ctx context.Context, plan, state *iscsiTargetResourceModel, diags *diag.Diagnostics) map[string]any {
 	params := map[string]any{
 		"name": plan.Name.ValueString(),
 	}
 
 	if !plan.Alias.IsNull() && !plan.Alias.IsUnknown() {
 		params["alias"] = plan.Alias.ValueString()
-	} else if plan.Alias.IsNull() {
+	} else if plan.Alias.IsNull() && state != nil {
 		params["alias"] = ""
 	}
 	if !plan.Mode.IsNull() && !plan.Mode.IsUnknown() {
@@ -229,52 +145,12 @@ func (r *iscsiTargetResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	if !plan.Groups.IsNull() && !plan.Groups.IsUnknown() {
-		groups := iscsiTargetGroupsFromPlan(ctx, plan.Groups, &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		params["groups"] = groups
-	} else if plan.Groups.IsNull() {
+		params["groups"] = iscsiTargetGroupsFromPlan(ctx, plan.Groups, diags)
+	} else if plan.Groups.IsNull() && state != nil {
 		params["groups"] = []map[string]any{}
 	}
 
-	var result iscsiTargetResult
-	err := r.client.Call(ctx, "iscsi.target.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating iSCSI Target", err.Error())
-		return
-	}
-
-	populateISCSITargetState(&plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *iscsiTargetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state iscsiTargetResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Pass false, false: no force, no cascade
-	err := r.client.Call(ctx, "iscsi.target.delete", []any{state.ID.ValueInt64(), false, false}, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting iSCSI Target", err.Error())
-		return
-	}
-}
-
-func (r *iscsiTargetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing iSCSI Target",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	return params
 }
 
 func iscsiTargetGroupsFromPlan(ctx context.Context, listVal types.List, diags *diag.Diagnostics) []map[string]any {
@@ -311,7 +187,7 @@ func iscsiTargetGroupsFromPlan(ctx context.Context, listVal types.List, diags *d
 	return result
 }
 
-func populateISCSITargetState(model *iscsiTargetResourceModel, result *iscsiTargetResult, diags *diag.Diagnostics) {
+func populateISCSITargetState(_ context.Context, model *iscsiTargetResourceModel, result *iscsiTargetResult, diags *diag.Diagnostics) {
 	model.ID = types.Int64Value(result.ID)
 	model.Name = types.StringValue(result.Name)
 	model.Mode = types.StringValue(result.Mode)
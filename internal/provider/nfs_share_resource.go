@@ -3,9 +3,8 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -24,24 +23,29 @@ var (
 	_ resource.Resource                = (*nfsShareResource)(nil)
 	_ resource.ResourceWithConfigure   = (*nfsShareResource)(nil)
 	_ resource.ResourceWithImportState = (*nfsShareResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*nfsShareResource)(nil)
 )
 
 type nfsShareResource struct {
-	client *client.Client
+	client   *client.Client
+	provider *providerData
 }
 
 type nfsShareResourceModel struct {
-	ID           types.Int64  `tfsdk:"id"`
-	Path         types.String `tfsdk:"path"`
-	Comment      types.String `tfsdk:"comment"`
-	Enabled      types.Bool   `tfsdk:"enabled"`
-	Networks     types.List   `tfsdk:"networks"`
-	Hosts        types.List   `tfsdk:"hosts"`
-	MaprootUser  types.String `tfsdk:"maproot_user"`
-	MaprootGroup types.String `tfsdk:"maproot_group"`
-	MapallUser   types.String `tfsdk:"mapall_user"`
-	MapallGroup  types.String `tfsdk:"mapall_group"`
-	Locked       types.Bool   `tfsdk:"locked"`
+	ID           types.Int64    `tfsdk:"id"`
+	Path         types.String   `tfsdk:"path"`
+	ResolvedPath types.String   `tfsdk:"resolved_path"`
+	Comment      types.String   `tfsdk:"comment"`
+	Enabled      types.Bool     `tfsdk:"enabled"`
+	Networks     types.List     `tfsdk:"networks"`
+	Hosts        types.List     `tfsdk:"hosts"`
+	MaprootUser  types.String   `tfsdk:"maproot_user"`
+	MaprootGroup types.String   `tfsdk:"maproot_group"`
+	MapallUser   types.String   `tfsdk:"mapall_user"`
+	MapallGroup  types.String   `tfsdk:"mapall_group"`
+	Locked       types.Bool     `tfsdk:"locked"`
+	OwnershipTag types.String   `tfsdk:"ownership_tag"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
 }
 
 type nfsShareResult struct {
@@ -66,7 +70,7 @@ func (r *nfsShareResource) Metadata(_ context.Context, req resource.MetadataRequ
 	resp.TypeName = req.ProviderTypeName + "_nfs_share"
 }
 
-func (r *nfsShareResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *nfsShareResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a TrueNAS NFS share.",
 		Attributes: map[string]schema.Attribute{
@@ -78,9 +82,10 @@ func (r *nfsShareResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				},
 			},
 			"path": schema.StringAttribute{
-				Description: "The filesystem path to share (e.g. /mnt/tank/data).",
+				Description: "The filesystem path to share (e.g. /mnt/tank/data), or a path relative to the provider's default_pool (e.g. ./data).",
 				Required:    true,
 			},
+			"resolved_path": resolvedPathAttribute("path", "/mnt/tank/data"),
 			"comment": schema.StringAttribute{
 				Description: "Description of the share.",
 				Optional:    true,
@@ -124,6 +129,8 @@ func (r *nfsShareResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"ownership_tag": ownershipTagAttribute("share", "at the end of its comment, as [terraform:<tag>]"),
+			"timeouts":      timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
@@ -133,16 +140,30 @@ func (r *nfsShareResource) Configure(_ context.Context, req resource.ConfigureRe
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
+	r.provider = data
+}
+
+func (r *nfsShareResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var p types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("path"), &p)...)
+	checkRelativePath(r.provider, path.Root("path"), p, &resp.Diagnostics)
+	planResolvedPath(ctx, r.provider, mountPath, path.Root("resolved_path"), p, resp)
+
+	planOwnershipTag(ctx, r.provider, req, resp)
 }
 
 func (r *nfsShareResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -152,13 +173,22 @@ func (r *nfsShareResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	params := map[string]any{
-		"path":    plan.Path.ValueString(),
+		"path":    resolvePoolPath(r.provider.defaultPool, mountPath, plan.Path.ValueString()),
 		"enabled": plan.Enabled.ValueBool(),
 	}
 
-	if !plan.Comment.IsNull() {
-		params["comment"] = plan.Comment.ValueString()
+	if comment := withOwnershipMarker(plan.Comment.ValueString(), plan.OwnershipTag.ValueString()); comment != "" {
+		params["comment"] = comment
 	}
 	if !plan.Networks.IsNull() {
 		var networks []string
@@ -196,7 +226,7 @@ func (r *nfsShareResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	populateNFSShareState(ctx, &plan, &result, &resp.Diagnostics)
+	populateNFSShareState(ctx, &plan, &result, r.provider.defaultPool, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -210,7 +240,7 @@ func (r *nfsShareResource) Read(ctx context.Context, req resource.ReadRequest, r
 	var result nfsShareResult
 	err := r.client.Call(ctx, "sharing.nfs.get_instance", []any{state.ID.ValueInt64()}, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -218,7 +248,7 @@ func (r *nfsShareResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	populateNFSShareState(ctx, &state, &result, &resp.Diagnostics)
+	populateNFSShareState(ctx, &state, &result, r.provider.defaultPool, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -229,6 +259,15 @@ func (r *nfsShareResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var state nfsShareResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -236,15 +275,11 @@ func (r *nfsShareResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	params := map[string]any{
-		"path":    plan.Path.ValueString(),
+		"path":    resolvePoolPath(r.provider.defaultPool, mountPath, plan.Path.ValueString()),
 		"enabled": plan.Enabled.ValueBool(),
 	}
 
-	if !plan.Comment.IsNull() {
-		params["comment"] = plan.Comment.ValueString()
-	} else {
-		params["comment"] = ""
-	}
+	params["comment"] = withOwnershipMarker(plan.Comment.ValueString(), plan.OwnershipTag.ValueString())
 	if !plan.Networks.IsNull() {
 		var networks []string
 		resp.Diagnostics.Append(plan.Networks.ElementsAs(ctx, &networks, false)...)
@@ -293,7 +328,7 @@ func (r *nfsShareResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	populateNFSShareState(ctx, &plan, &result, &resp.Diagnostics)
+	populateNFSShareState(ctx, &plan, &result, r.provider.defaultPool, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -304,6 +339,15 @@ func (r *nfsShareResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := r.client.Call(ctx, "sharing.nfs.delete", []any{state.ID.ValueInt64()}, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error Deleting NFS Share", err.Error())
@@ -312,27 +356,44 @@ func (r *nfsShareResource) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (r *nfsShareResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
+	id, err := resolveImportID(ctx, r.client, req.ID, lookupNFSShare)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Importing NFS Share",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
+			fmt.Sprintf("Could not resolve import ID %q: %s", req.ID, err.Error()),
 		)
 		return
 	}
 
+	// The ownership check reads the object, which is skipped before the provider is
+	// configured.
+	if r.client != nil {
+		var result nfsShareResult
+		if err := r.client.Call(ctx, "sharing.nfs.get_instance", []any{id}, &result); err == nil {
+			_, tag := splitOwnershipMarker(result.Comment)
+			checkImportOwnership(r.provider, "NFS Share", result.Path, tag, &resp.Diagnostics)
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
-func populateNFSShareState(ctx context.Context, model *nfsShareResourceModel, result *nfsShareResult, diags *diag.Diagnostics) {
+func populateNFSShareState(ctx context.Context, model *nfsShareResourceModel, result *nfsShareResult, defaultPool string, diags *diag.Diagnostics) {
 	model.ID = types.Int64Value(result.ID)
-	model.Path = types.StringValue(result.Path)
+	model.Path = keepRelativePath(defaultPool, mountPath, model.Path, result.Path)
+	model.ResolvedPath = types.StringValue(result.Path)
 
-	if result.Comment != "" {
-		model.Comment = types.StringValue(result.Comment)
+	comment, tag := splitOwnershipMarker(result.Comment)
+	if comment != "" {
+		model.Comment = types.StringValue(comment)
 	} else {
 		model.Comment = types.StringNull()
 	}
+	if tag != "" {
+		model.OwnershipTag = types.StringValue(tag)
+	} else {
+		model.OwnershipTag = types.StringNull()
+	}
 
 	model.Enabled = types.BoolValue(result.Enabled)
 
@@ -27,6 +27,12 @@ func TestAccNFSShareResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "truenas_nfs_share.test",
+				ImportState:       true,
+				ImportStateId:     "/mnt/" + dsName,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -59,7 +65,8 @@ func TestAccNFSShareResource_withOptions(t *testing.T) {
 func testAccNFSShareResourceConfig(dsName string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test_nfs" {
-  name = %q
+  name                = %q
+  deletion_protection = false
 }
 
 resource "truenas_nfs_share" "test" {
@@ -71,7 +78,8 @@ resource "truenas_nfs_share" "test" {
 func testAccNFSShareResourceConfigWithOptions(dsName string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test_nfs" {
-  name = %q
+  name                = %q
+  deletion_protection = false
 }
 
 resource "truenas_nfs_share" "test" {
@@ -85,7 +93,8 @@ resource "truenas_nfs_share" "test" {
 func testAccNFSShareResourceConfigWithOptionsUpdate(dsName string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test_nfs" {
-  name = %q
+  name                = %q
+  deletion_protection = false
 }
 
 resource "truenas_nfs_share" "test" {
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = (*nqnFunction)(nil)
+
+type nqnFunction struct{}
+
+// NVMe NQNs are limited to 223 bytes; TrueNAS additionally rejects anything shorter than 11.
+const (
+	nqnMinLength = 11
+	nqnMaxLength = 223
+)
+
+var nqnBasenameRegexp = regexp.MustCompile(`^nqn\.\d{4}-\d{2}\.[A-Za-z0-9][A-Za-z0-9.-]*(:[^\s]+)?$`)
+
+func NewNQNFunction() function.Function {
+	return &nqnFunction{}
+}
+
+func (f *nqnFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "nqn"
+}
+
+func (f *nqnFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build the NVMe qualified name of a subsystem.",
+		MarkdownDescription: "Builds the subsystem NQN that TrueNAS generates for an `nvmet_subsys` without an explicit `subnqn`, " +
+			"i.e. `<basenqn>:<name>`. The result must be between 11 and 223 characters long.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "basenqn",
+				Description: "The global NVMe-oF base NQN (e.g. nqn.2011-06.com.truenas).",
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "The subsystem name, as set on truenas_nvmet_subsys.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *nqnFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var basenqn, name string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &basenqn, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	if !nqnBasenameRegexp.MatchString(basenqn) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf(
+			"invalid basenqn %q: expected the form nqn.YYYY-MM.reversed.domain", basenqn))
+		return
+	}
+	if name == "" || strings.ContainsFunc(name, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' }) {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid subsystem name %q: must be non-empty and contain no whitespace", name))
+		return
+	}
+
+	nqn := basenqn + ":" + name
+	if len(nqn) < nqnMinLength || len(nqn) > nqnMaxLength {
+		resp.Error = function.NewFuncError(fmt.Sprintf(
+			"resulting NQN %q is %d characters long, must be between %d and %d", nqn, len(nqn), nqnMinLength, nqnMaxLength))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, nqn))
+}
@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNQNFunction(t *testing.T) {
+	call := func(basenqn, name string) string {
+		return `provider::truenas::nqn("` + basenqn + `", "` + name + `")`
+	}
+
+	testFunction(t, map[string]functionTestCase{
+		"default basenqn": {expr: call("nqn.2011-06.com.truenas", "vm-disks"), want: "nqn.2011-06.com.truenas:vm-disks"},
+		"empty name":      {expr: call("nqn.2011-06.com.truenas", ""), wantErr: `invalid subsystem name`},
+		"whitespace name": {expr: call("nqn.2011-06.com.truenas", "vm disks"), wantErr: `invalid subsystem name`},
+		"bad basenqn":     {expr: call("iqn.2011-06.com.truenas", "vm"), wantErr: `invalid basenqn`},
+		"too long":        {expr: call("nqn.2011-06.com.truenas", strings.Repeat("a", 200)), wantErr: `characters long`},
+	})
+}
@@ -39,7 +39,7 @@ func (d *nvmetGlobalDataSource) Metadata(_ context.Context, req datasource.Metad
 
 func (d *nvmetGlobalDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Fetches the TrueNAS NVMe-oF global configuration.",
+		Description: "Fetches the TrueNAS NVMe-oF global configuration. " + capabilityNote(capNVMeT),
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Description: "The unique identifier of the NVMe-oF global configuration.",
@@ -74,19 +74,24 @@ func (d *nvmetGlobalDataSource) Configure(_ context.Context, req datasource.Conf
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = c
+	d.client = data.client
 }
 
 func (d *nvmetGlobalDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	requireCapability(d.client, capNVMeT, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var result nvmetGlobalResult
 	err := d.client.Call(ctx, "nvmet.global.config", nil, &result)
 	if err != nil {
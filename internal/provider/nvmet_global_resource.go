@@ -3,13 +3,15 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/barodeur/terraform-provider-truenas/internal/client"
@@ -19,6 +21,7 @@ var (
 	_ resource.Resource                = (*nvmetGlobalResource)(nil)
 	_ resource.ResourceWithConfigure   = (*nvmetGlobalResource)(nil)
 	_ resource.ResourceWithImportState = (*nvmetGlobalResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*nvmetGlobalResource)(nil)
 )
 
 type nvmetGlobalResource struct {
@@ -26,21 +29,22 @@ type nvmetGlobalResource struct {
 }
 
 type nvmetGlobalResourceModel struct {
-	ID           types.Int64  `tfsdk:"id"`
-	Basenqn      types.String `tfsdk:"basenqn"`
-	Kernel       types.Bool   `tfsdk:"kernel"`
-	ANA          types.Bool   `tfsdk:"ana"`
-	RDMA         types.Bool   `tfsdk:"rdma"`
-	XportReferral types.Bool  `tfsdk:"xport_referral"`
+	ID            types.Int64    `tfsdk:"id"`
+	Basenqn       types.String   `tfsdk:"basenqn"`
+	Kernel        types.Bool     `tfsdk:"kernel"`
+	ANA           types.Bool     `tfsdk:"ana"`
+	RDMA          types.Bool     `tfsdk:"rdma"`
+	XportReferral types.Bool     `tfsdk:"xport_referral"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 type nvmetGlobalResult struct {
-	ID           int64  `json:"id"`
-	Basenqn      string `json:"basenqn"`
-	Kernel       bool   `json:"kernel"`
-	ANA          bool   `json:"ana"`
-	RDMA         bool   `json:"rdma"`
-	XportReferral bool  `json:"xport_referral"`
+	ID            int64  `json:"id"`
+	Basenqn       string `json:"basenqn"`
+	Kernel        bool   `json:"kernel"`
+	ANA           bool   `json:"ana"`
+	RDMA          bool   `json:"rdma"`
+	XportReferral bool   `json:"xport_referral"`
 }
 
 func NewNVMeTGlobalResource() resource.Resource {
@@ -51,9 +55,9 @@ func (r *nvmetGlobalResource) Metadata(_ context.Context, req resource.MetadataR
 	resp.TypeName = req.ProviderTypeName + "_nvmet_global"
 }
 
-func (r *nvmetGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *nvmetGlobalResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages the TrueNAS NVMe-oF global configuration. This is a singleton resource.",
+		Description: "Manages the TrueNAS NVMe-oF global configuration. This is a singleton resource. " + capabilityNote(capNVMeT),
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Description: "The unique identifier of the NVMe-oF global configuration.",
@@ -66,6 +70,9 @@ func (r *nvmetGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "NQN prefix used for subsystem creation (11–223 characters).",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(nqnMinLength, nqnMaxLength),
+				},
 			},
 			"kernel": schema.BoolAttribute{
 				Description: "NVMe-oF backend selection.",
@@ -87,6 +94,7 @@ func (r *nvmetGlobalResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Optional:    true,
 				Computed:    true,
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true}),
 		},
 	}
 }
@@ -96,16 +104,23 @@ func (r *nvmetGlobalResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
+}
+
+func (r *nvmetGlobalResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	requireCapability(r.client, capNVMeT, &resp.Diagnostics)
 }
 
 func (r *nvmetGlobalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -115,6 +130,15 @@ func (r *nvmetGlobalResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	params := map[string]any{}
 
 	if !plan.Basenqn.IsNull() && !plan.Basenqn.IsUnknown() {
@@ -169,6 +193,15 @@ func (r *nvmetGlobalResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	params := map[string]any{}
 
 	if !plan.Basenqn.IsNull() && !plan.Basenqn.IsUnknown() {
@@ -206,7 +239,7 @@ func (r *nvmetGlobalResource) ImportState(ctx context.Context, _ resource.Import
 	var result nvmetGlobalResult
 	err := r.client.Call(ctx, "nvmet.global.config", nil, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
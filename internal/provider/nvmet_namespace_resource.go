@@ -3,9 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -13,32 +14,38 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/barodeur/terraform-provider-truenas/internal/client"
 )
 
 var (
-	_ resource.Resource                = (*nvmetNamespaceResource)(nil)
-	_ resource.ResourceWithConfigure   = (*nvmetNamespaceResource)(nil)
-	_ resource.ResourceWithImportState = (*nvmetNamespaceResource)(nil)
+	_ resource.Resource                 = (*nvmetNamespaceResource)(nil)
+	_ resource.ResourceWithConfigure    = (*nvmetNamespaceResource)(nil)
+	_ resource.ResourceWithImportState  = (*nvmetNamespaceResource)(nil)
+	_ resource.ResourceWithModifyPlan   = (*nvmetNamespaceResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*nvmetNamespaceResource)(nil)
 )
 
 type nvmetNamespaceResource struct {
-	client *client.Client
+	client   *client.Client
+	provider *providerData
 }
 
 type nvmetNamespaceResourceModel struct {
-	ID          types.Int64  `tfsdk:"id"`
-	NSID        types.Int64  `tfsdk:"nsid"`
-	SubsysID    types.Int64  `tfsdk:"subsys_id"`
-	DeviceType  types.String `tfsdk:"device_type"`
-	DevicePath  types.String `tfsdk:"device_path"`
-	Filesize    types.Int64  `tfsdk:"filesize"`
-	Enabled     types.Bool   `tfsdk:"enabled"`
-	DeviceUUID  types.String `tfsdk:"device_uuid"`
-	DeviceNGUID types.String `tfsdk:"device_nguid"`
-	Locked      types.Bool   `tfsdk:"locked"`
+	ID                 types.Int64    `tfsdk:"id"`
+	NSID               types.Int64    `tfsdk:"nsid"`
+	SubsysID           types.Int64    `tfsdk:"subsys_id"`
+	DeviceType         types.String   `tfsdk:"device_type"`
+	DevicePath         types.String   `tfsdk:"device_path"`
+	ResolvedDevicePath types.String   `tfsdk:"resolved_device_path"`
+	Filesize           sizeValue      `tfsdk:"filesize"`
+	Enabled            types.Bool     `tfsdk:"enabled"`
+	DeviceUUID         types.String   `tfsdk:"device_uuid"`
+	DeviceNGUID        types.String   `tfsdk:"device_nguid"`
+	Locked             types.Bool     `tfsdk:"locked"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type nvmetNamespaceResultSubsys struct {
@@ -46,16 +53,16 @@ type nvmetNamespaceResultSubsys struct {
 }
 
 type nvmetNamespaceResult struct {
-	ID          int64                     `json:"id"`
-	NSID        int64                     `json:"nsid"`
+	ID          int64                      `json:"id"`
+	NSID        int64                      `json:"nsid"`
 	Subsys      nvmetNamespaceResultSubsys `json:"subsys"`
-	DeviceType  string                    `json:"device_type"`
-	DevicePath  string                    `json:"device_path"`
-	Filesize    *int64                    `json:"filesize"`
-	Enabled     bool                      `json:"enabled"`
-	DeviceUUID  string                    `json:"device_uuid"`
-	DeviceNGUID string                    `json:"device_nguid"`
-	Locked      bool                      `json:"locked"`
+	DeviceType  string                     `json:"device_type"`
+	DevicePath  string                     `json:"device_path"`
+	Filesize    *int64                     `json:"filesize"`
+	Enabled     bool                       `json:"enabled"`
+	DeviceUUID  string                     `json:"device_uuid"`
+	DeviceNGUID string                     `json:"device_nguid"`
+	Locked      bool                       `json:"locked"`
 }
 
 func NewNVMeTNamespaceResource() resource.Resource {
@@ -66,9 +73,10 @@ func (r *nvmetNamespaceResource) Metadata(_ context.Context, req resource.Metada
 	resp.TypeName = req.ProviderTypeName + "_nvmet_namespace"
 }
 
-func (r *nvmetNamespaceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *nvmetNamespaceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages a TrueNAS NVMe-oF namespace.",
+		Version:     1,
+		Description: "Manages a TrueNAS NVMe-oF namespace. " + capabilityNote(capNVMeT),
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Description: "The unique identifier of the NVMe-oF namespace.",
@@ -81,6 +89,9 @@ func (r *nvmetNamespaceResource) Schema(_ context.Context, _ resource.SchemaRequ
 				Description: "Namespace ID (1 to 4294967294). Auto-assigned if omitted.",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 4294967294),
+				},
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
 				},
@@ -95,14 +106,19 @@ func (r *nvmetNamespaceResource) Schema(_ context.Context, _ resource.SchemaRequ
 			"device_type": schema.StringAttribute{
 				Description: "Device type (ZVOL or FILE).",
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ZVOL", "FILE"),
+				},
 			},
 			"device_path": schema.StringAttribute{
-				Description: "Path to the zvol or file.",
+				Description: "Path to the zvol (e.g. zvol/tank/vols/ns1) or file, or a path relative to the provider's default_pool (e.g. ./vols/ns1).",
 				Required:    true,
 			},
-			"filesize": schema.Int64Attribute{
-				Description: "Size in bytes when device_type is FILE.",
+			"resolved_device_path": resolvedPathAttribute("device_path", "zvol/tank/vols/ns1"),
+			"filesize": schema.StringAttribute{
+				Description: "Size when device_type is FILE, as bytes or with a unit such as \"50GiB\".",
 				Optional:    true,
+				CustomType:  sizeType{},
 			},
 			"enabled": schema.BoolAttribute{
 				Description: "Whether the namespace is enabled. Defaults to true.",
@@ -128,6 +144,7 @@ func (r *nvmetNamespaceResource) Schema(_ context.Context, _ resource.SchemaRequ
 				Description: "Whether the namespace is locked.",
 				Computed:    true,
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
@@ -137,16 +154,34 @@ func (r *nvmetNamespaceResource) Configure(_ context.Context, req resource.Confi
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
+	r.provider = data
+}
+
+func (r *nvmetNamespaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	requireCapability(r.client, capNVMeT, &resp.Diagnostics)
+
+	var devicePath, deviceType types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("device_path"), &devicePath)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("device_type"), &deviceType)...)
+	checkRelativePath(r.provider, path.Root("device_path"), devicePath, &resp.Diagnostics)
+	if deviceType.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_device_path"), types.StringUnknown())...)
+	} else {
+		planResolvedPath(ctx, r.provider, devicePathKind(deviceType.ValueString()), path.Root("resolved_device_path"), devicePath, resp)
+	}
 }
 
 func (r *nvmetNamespaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -156,10 +191,19 @@ func (r *nvmetNamespaceResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	params := map[string]any{
 		"subsys_id":   plan.SubsysID.ValueInt64(),
 		"device_type": plan.DeviceType.ValueString(),
-		"device_path": plan.DevicePath.ValueString(),
+		"device_path": resolvePoolPath(r.provider.defaultPool, devicePathKind(plan.DeviceType.ValueString()), plan.DevicePath.ValueString()),
 		"enabled":     plan.Enabled.ValueBool(),
 	}
 
@@ -167,7 +211,7 @@ func (r *nvmetNamespaceResource) Create(ctx context.Context, req resource.Create
 		params["nsid"] = plan.NSID.ValueInt64()
 	}
 	if !plan.Filesize.IsNull() && !plan.Filesize.IsUnknown() {
-		params["filesize"] = plan.Filesize.ValueInt64()
+		params["filesize"] = plan.Filesize.ValueBytes()
 	}
 
 	var result nvmetNamespaceResult
@@ -177,7 +221,7 @@ func (r *nvmetNamespaceResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	populateNVMeTNamespaceState(&plan, &result)
+	populateNVMeTNamespaceState(&plan, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -191,7 +235,7 @@ func (r *nvmetNamespaceResource) Read(ctx context.Context, req resource.ReadRequ
 	var result nvmetNamespaceResult
 	err := r.client.Call(ctx, "nvmet.namespace.get_instance", []any{state.ID.ValueInt64()}, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -199,7 +243,7 @@ func (r *nvmetNamespaceResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	populateNVMeTNamespaceState(&state, &result)
+	populateNVMeTNamespaceState(&state, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -210,6 +254,15 @@ func (r *nvmetNamespaceResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var state nvmetNamespaceResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -219,12 +272,12 @@ func (r *nvmetNamespaceResource) Update(ctx context.Context, req resource.Update
 	params := map[string]any{
 		"subsys_id":   plan.SubsysID.ValueInt64(),
 		"device_type": plan.DeviceType.ValueString(),
-		"device_path": plan.DevicePath.ValueString(),
+		"device_path": resolvePoolPath(r.provider.defaultPool, devicePathKind(plan.DeviceType.ValueString()), plan.DevicePath.ValueString()),
 		"enabled":     plan.Enabled.ValueBool(),
 	}
 
 	if !plan.Filesize.IsNull() {
-		params["filesize"] = plan.Filesize.ValueInt64()
+		params["filesize"] = plan.Filesize.ValueBytes()
 	} else {
 		params["filesize"] = nil
 	}
@@ -236,7 +289,7 @@ func (r *nvmetNamespaceResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	populateNVMeTNamespaceState(&plan, &result)
+	populateNVMeTNamespaceState(&plan, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -247,9 +300,18 @@ func (r *nvmetNamespaceResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := r.client.Call(ctx, "nvmet.namespace.delete", []any{state.ID.ValueInt64()}, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Error Deleting NVMe-oF Namespace", err.Error())
@@ -258,11 +320,11 @@ func (r *nvmetNamespaceResource) Delete(ctx context.Context, req resource.Delete
 }
 
 func (r *nvmetNamespaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
+	id, err := resolveImportID(ctx, r.client, req.ID, lookupNVMeTNamespace)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Importing NVMe-oF Namespace",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
+			fmt.Sprintf("Could not resolve import ID %q: %s", req.ID, err.Error()),
 		)
 		return
 	}
@@ -270,17 +332,35 @@ func (r *nvmetNamespaceResource) ImportState(ctx context.Context, req resource.I
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
-func populateNVMeTNamespaceState(model *nvmetNamespaceResourceModel, result *nvmetNamespaceResult) {
+// UpgradeState migrates state written by earlier schema versions.
+//
+// Version 1 changed filesize from a number to a string accepting units.
+func (r *nvmetNamespaceResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return stateUpgraders(
+		numbersToStrings("filesize"),
+	)
+}
+
+// devicePathKind returns how a relative device_path resolves for a device type.
+func devicePathKind(deviceType string) poolPathKind {
+	if deviceType == "ZVOL" {
+		return zvolPath
+	}
+	return mountPath
+}
+
+func populateNVMeTNamespaceState(model *nvmetNamespaceResourceModel, result *nvmetNamespaceResult, defaultPool string) {
 	model.ID = types.Int64Value(result.ID)
 	model.NSID = types.Int64Value(result.NSID)
 	model.SubsysID = types.Int64Value(result.Subsys.ID)
 	model.DeviceType = types.StringValue(result.DeviceType)
-	model.DevicePath = types.StringValue(result.DevicePath)
+	model.DevicePath = keepRelativePath(defaultPool, devicePathKind(result.DeviceType), model.DevicePath, result.DevicePath)
+	model.ResolvedDevicePath = types.StringValue(result.DevicePath)
 
 	if result.Filesize != nil && *result.Filesize != 0 {
-		model.Filesize = types.Int64Value(*result.Filesize)
+		model.Filesize = sizeBytes(*result.Filesize)
 	} else {
-		model.Filesize = types.Int64Null()
+		model.Filesize = sizeNull()
 	}
 
 	model.Enabled = types.BoolValue(result.Enabled)
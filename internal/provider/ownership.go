@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ownershipProperty is the ZFS user property holding the ownership tag of a dataset.
+const ownershipProperty = "org.terraform:workspace"
+
+// ownershipTagPattern restricts tags to characters that can be stored both in a ZFS
+// user property and in the comment marker without escaping.
+var ownershipTagPattern = regexp.MustCompile(`^[A-Za-z0-9._:/@-]{1,128}$`)
+
+// ownershipMarkerPattern matches the marker appended to comment fields, e.g.
+// "Media share [terraform:prod]".
+var ownershipMarkerPattern = regexp.MustCompile(`(?:^| )\[terraform:([A-Za-z0-9._:/@-]{1,128})\]$`)
+
+func validateOwnershipTag(tag string) error {
+	if tag == "" || ownershipTagPattern.MatchString(tag) {
+		return nil
+	}
+	return fmt.Errorf("ownership tag %q must be at most 128 letters, digits or . _ - : / @ characters", tag)
+}
+
+// ownershipTagAttribute is the computed "ownership_tag" attribute of resources that
+// carry the provider's ownership tag.
+func ownershipTagAttribute(kind, location string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("The ownership tag of the %s, stored %s. Set from the provider's ownership_tag; "+
+			"when the provider has none, an existing tag is kept.", kind, location),
+		Computed: true,
+	}
+}
+
+// planOwnershipTag sets the planned ownership_tag of a non-destroy plan to the
+// provider's tag, or keeps the current one when the provider has none. A changed
+// provider tag therefore shows as an update that rewrites the tag.
+func planOwnershipTag(ctx context.Context, d *providerData, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	planned := types.StringNull()
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("ownership_tag"), &planned)...)
+	}
+	if tag := providerOwnershipTag(d); tag != "" {
+		planned = types.StringValue(tag)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ownership_tag"), planned)...)
+}
+
+func providerOwnershipTag(d *providerData) string {
+	if d == nil {
+		return ""
+	}
+	return d.ownershipTag
+}
+
+// checkImportOwnership warns when an imported object carries the tag of another
+// workspace. Untagged objects are tagged by the next apply without a warning.
+func checkImportOwnership(d *providerData, kind, name, found string, diags *diag.Diagnostics) {
+	tag := providerOwnershipTag(d)
+	if tag == "" || found == "" || found == tag {
+		return
+	}
+	diags.AddWarning(
+		"Importing "+kind+" Owned by Another Workspace",
+		fmt.Sprintf("%s %q has the ownership tag %q, but this provider is configured with %q. "+
+			"It may be managed by another Terraform workspace; the next apply will replace its tag.", kind, name, found, tag),
+	)
+}
+
+// withOwnershipMarker appends the marker for tag to a comment field.
+func withOwnershipMarker(comment, tag string) string {
+	if tag == "" {
+		return comment
+	}
+	marker := "[terraform:" + tag + "]"
+	if comment == "" {
+		return marker
+	}
+	return comment + " " + marker
+}
+
+// splitOwnershipMarker splits a comment field written by withOwnershipMarker into the
+// comment and the tag.
+func splitOwnershipMarker(s string) (comment, tag string) {
+	m := ownershipMarkerPattern.FindStringSubmatchIndex(s)
+	if m == nil {
+		return s, ""
+	}
+	return s[:m[0]], s[m[2]:m[3]]
+}
@@ -0,0 +1,52 @@
+package provider
+
+import "testing"
+
+func TestOwnershipMarker(t *testing.T) {
+	tests := []struct {
+		comment string
+		tag     string
+		stored  string
+	}{
+		{comment: "Media share", tag: "prod", stored: "Media share [terraform:prod]"},
+		{comment: "", tag: "infra/nas@eu-1", stored: "[terraform:infra/nas@eu-1]"},
+		{comment: "Media share", tag: "", stored: "Media share"},
+		{comment: "[not a marker]", tag: "", stored: "[not a marker]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stored, func(t *testing.T) {
+			if got := withOwnershipMarker(tt.comment, tt.tag); got != tt.stored {
+				t.Errorf("withOwnershipMarker(%q, %q) = %q, want %q", tt.comment, tt.tag, got, tt.stored)
+			}
+			comment, tag := splitOwnershipMarker(tt.stored)
+			if comment != tt.comment || tag != tt.tag {
+				t.Errorf("splitOwnershipMarker(%q) = %q, %q, want %q, %q", tt.stored, comment, tag, tt.comment, tt.tag)
+			}
+		})
+	}
+}
+
+func TestSplitOwnershipMarkerIgnoresEmbeddedMarker(t *testing.T) {
+	comment, tag := splitOwnershipMarker("Moved from [terraform:old] by hand")
+	if comment != "Moved from [terraform:old] by hand" || tag != "" {
+		t.Errorf("unexpected split: %q, %q", comment, tag)
+	}
+	comment, tag = splitOwnershipMarker("Backups[terraform:prod]")
+	if comment != "Backups[terraform:prod]" || tag != "" {
+		t.Errorf("a marker must be separated from the comment by a space, got %q, %q", comment, tag)
+	}
+}
+
+func TestValidateOwnershipTag(t *testing.T) {
+	for _, tag := range []string{"", "prod", "team-a/nas_1", "git@example.com:infra.git"} {
+		if err := validateOwnershipTag(tag); err != nil {
+			t.Errorf("validateOwnershipTag(%q): unexpected error %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"with space", "a]b", "line\nbreak"} {
+		if err := validateOwnershipTag(tag); err == nil {
+			t.Errorf("validateOwnershipTag(%q): expected an error", tag)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = (*parseSizeFunction)(nil)
+
+type parseSizeFunction struct{}
+
+// sizeUnits maps the upper-cased unit suffixes accepted by parseSize to their
+// multiplier. Following ZFS conventions, every unit is a power of 1024
+// regardless of whether it is written as "G", "GB" or "GiB".
+var sizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KB":  1 << 10,
+	"KIB": 1 << 10,
+	"M":   1 << 20,
+	"MB":  1 << 20,
+	"MIB": 1 << 20,
+	"G":   1 << 30,
+	"GB":  1 << 30,
+	"GIB": 1 << 30,
+	"T":   1 << 40,
+	"TB":  1 << 40,
+	"TIB": 1 << 40,
+	"P":   1 << 50,
+	"PB":  1 << 50,
+	"PIB": 1 << 50,
+	"E":   1 << 60,
+	"EB":  1 << 60,
+	"EIB": 1 << 60,
+}
+
+func NewParseSizeFunction() function.Function {
+	return &parseSizeFunction{}
+}
+
+func (f *parseSizeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_size"
+}
+
+func (f *parseSizeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parse a human-readable size into a number of bytes.",
+		MarkdownDescription: "Parses a size such as `\"1.5TiB\"`, `\"50G\"` or `\"4096\"` into a number of bytes. " +
+			"Units are case-insensitive and, following ZFS conventions, always powers of 1024 " +
+			"(`K`, `KB` and `KiB` are all 1024 bytes). Fractional results are rounded down to whole bytes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "size",
+				Description: "The size to parse, e.g. \"1.5TiB\".",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *parseSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &size))
+	if resp.Error != nil {
+		return
+	}
+
+	bytes, err := parseSize(size)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, bytes))
+}
+
+// parseSize converts a human-readable size (e.g. "1.5TiB", "50G", "4096") into bytes.
+func parseSize(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return 0, fmt.Errorf("size must not be empty")
+	}
+
+	split := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := trimmed, ""
+	if split >= 0 {
+		number, unit = trimmed[:split], strings.TrimSpace(trimmed[split:])
+	}
+
+	multiplier, ok := sizeUnits[strings.ToUpper(unit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unit)
+	}
+
+	value, ok := new(big.Rat).SetString(number)
+	if !ok || number == "" || strings.HasPrefix(number, ".") || strings.HasSuffix(number, ".") {
+		return 0, fmt.Errorf("invalid size %q: %q is not a number", s, number)
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(multiplier))
+	bytes := new(big.Int).Quo(value.Num(), value.Denom())
+	if !bytes.IsInt64() {
+		return 0, fmt.Errorf("invalid size %q: value is too large", s)
+	}
+
+	return bytes.Int64(), nil
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestParseSizeFunction(t *testing.T) {
+	call := func(size string) string {
+		return `tostring(provider::truenas::parse_size("` + size + `"))`
+	}
+
+	testFunction(t, map[string]functionTestCase{
+		"plain integer":     {expr: call("4096"), want: "4096"},
+		"bytes suffix":      {expr: call("512B"), want: "512"},
+		"binary unit":       {expr: call("50GiB"), want: "53687091200"},
+		"short unit":        {expr: call("50G"), want: "53687091200"},
+		"zfs style unit":    {expr: call("50GB"), want: "53687091200"},
+		"fractional":        {expr: call("1.5TiB"), want: "1649267441664"},
+		"lowercase":         {expr: call("128k"), want: "131072"},
+		"whitespace":        {expr: call(" 1 MiB "), want: "1048576"},
+		"rounds down":       {expr: call("1.0001K"), want: "1024"},
+		"empty":             {expr: call(""), wantErr: `size must not be empty`},
+		"unknown unit":      {expr: call("10XB"), wantErr: `unknown unit`},
+		"missing number":    {expr: call("GiB"), wantErr: `is not a number`},
+		"trailing dot":      {expr: call("1.G"), wantErr: `is not a number`},
+		"negative":          {expr: call("-1G"), wantErr: `unknown unit`},
+		"overflow":          {expr: call("16EiB"), wantErr: `value is too large`},
+		"multiple decimals": {expr: call("1.2.3G"), wantErr: `is not a number`},
+	})
+}
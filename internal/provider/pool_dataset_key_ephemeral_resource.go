@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = (*poolDatasetKeyEphemeralResource)(nil)
+	_ ephemeral.EphemeralResourceWithConfigure = (*poolDatasetKeyEphemeralResource)(nil)
+)
+
+type poolDatasetKeyEphemeralResource struct {
+	client   *client.Client
+	provider *providerData
+}
+
+type poolDatasetKeyEphemeralResourceModel struct {
+	Dataset types.String `tfsdk:"dataset"`
+	Key     types.String `tfsdk:"key"`
+}
+
+func NewPoolDatasetKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &poolDatasetKeyEphemeralResource{}
+}
+
+func (e *poolDatasetKeyEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pool_dataset_key"
+}
+
+func (e *poolDatasetKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exports the encryption key of a dataset encrypted with a key (not a passphrase), e.g. to escrow it in a secrets manager. " +
+			"The key is never stored in the plan or state. Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"dataset": schema.StringAttribute{
+				Description: "The encryption root whose key to export, e.g. \"tank/tenants/acme\", or a path relative to the provider's default_pool.",
+				Required:    true,
+			},
+			"key": schema.StringAttribute{
+				Description: "The hex-encoded encryption key.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (e *poolDatasetKeyEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = data.client
+	e.provider = data
+}
+
+func (e *poolDatasetKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data poolDatasetKeyEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if e.client == nil {
+		resp.Diagnostics.AddError("Unconfigured Provider",
+			"The dataset key cannot be exported before the provider is configured.")
+		return
+	}
+
+	name := resolvePoolPath(e.provider.defaultPool, datasetNamePath, data.Dataset.ValueString())
+
+	// export_key is a job: without download, the key is the result of the job.
+	var key string
+	err := e.client.CallJob(ctx, "pool.dataset.export_key", []any{name, false}, &key)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Exporting Dataset Key", err.Error())
+		return
+	}
+
+	data.Key = types.StringValue(key)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
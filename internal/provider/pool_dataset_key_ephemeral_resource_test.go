@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccPoolDatasetKeyEphemeralResource_basic(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-key-export"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"truenas": providerserver.NewProtocol6WithError(New()()),
+			"echo":    echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetKeyEphemeralResourceConfig(dsName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("echo.test", "data.key", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccPoolDatasetKeyEphemeralResourceConfig(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                    = %q
+  encryption              = true
+  encryption_generate_key = true
+  deletion_protection     = false
+}
+
+ephemeral "truenas_pool_dataset_key" "test" {
+  dataset = truenas_pool_dataset.test.name
+}
+
+provider "echo" {
+  data = ephemeral.truenas_pool_dataset_key.test
+}
+
+resource "echo" "test" {}
+`, name)
+}
@@ -4,54 +4,86 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/barodeur/terraform-provider-truenas/internal/client"
 )
 
 var (
-	_ resource.Resource                = (*poolDatasetResource)(nil)
-	_ resource.ResourceWithConfigure   = (*poolDatasetResource)(nil)
-	_ resource.ResourceWithImportState = (*poolDatasetResource)(nil)
+	_ resource.Resource                   = (*poolDatasetResource)(nil)
+	_ resource.ResourceWithConfigure      = (*poolDatasetResource)(nil)
+	_ resource.ResourceWithImportState    = (*poolDatasetResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*poolDatasetResource)(nil)
+	_ resource.ResourceWithUpgradeState   = (*poolDatasetResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*poolDatasetResource)(nil)
 )
 
+// minDatasetQuota is the smallest non-zero quota TrueNAS accepts (1 GiB).
+const minDatasetQuota = 1 << 30
+
 type poolDatasetResource struct {
-	client *client.Client
+	client   *client.Client
+	provider *providerData
 }
 
 type poolDatasetResourceModel struct {
-	ID              types.String `tfsdk:"id"`
-	Name            types.String `tfsdk:"name"`
-	Pool            types.String `tfsdk:"pool"`
-	Comments        types.String `tfsdk:"comments"`
-	Sync            types.String `tfsdk:"sync"`
-	Compression     types.String `tfsdk:"compression"`
-	Atime           types.String `tfsdk:"atime"`
-	Exec            types.String `tfsdk:"exec"`
-	Readonly        types.String `tfsdk:"readonly"`
-	Deduplication   types.String `tfsdk:"deduplication"`
-	Checksum        types.String `tfsdk:"checksum"`
-	Copies          types.Int64  `tfsdk:"copies"`
-	Snapdir         types.String `tfsdk:"snapdir"`
-	Quota           types.Int64  `tfsdk:"quota"`
-	Refquota        types.Int64  `tfsdk:"refquota"`
-	Reservation     types.Int64  `tfsdk:"reservation"`
-	Refreservation  types.Int64  `tfsdk:"refreservation"`
-	Recordsize      types.String `tfsdk:"recordsize"`
-	Aclmode         types.String `tfsdk:"aclmode"`
-	Acltype         types.String `tfsdk:"acltype"`
-	Casesensitivity types.String `tfsdk:"casesensitivity"`
-	CreateAncestors types.Bool   `tfsdk:"create_ancestors"`
-	Mountpoint      types.String `tfsdk:"mountpoint"`
-	Encrypted       types.Bool   `tfsdk:"encrypted"`
+	datasetPropertiesModel
+
+	ID                    types.String   `tfsdk:"id"`
+	Name                  types.String   `tfsdk:"name"`
+	ResolvedName          types.String   `tfsdk:"resolved_name"`
+	Pool                  types.String   `tfsdk:"pool"`
+	Atime                 types.String   `tfsdk:"atime"`
+	Exec                  types.String   `tfsdk:"exec"`
+	Snapdir               types.String   `tfsdk:"snapdir"`
+	Quota                 sizeValue      `tfsdk:"quota"`
+	Refquota              sizeValue      `tfsdk:"refquota"`
+	Reservation           sizeValue      `tfsdk:"reservation"`
+	Refreservation        sizeValue      `tfsdk:"refreservation"`
+	Recordsize            types.String   `tfsdk:"recordsize"`
+	Aclmode               types.String   `tfsdk:"aclmode"`
+	Acltype               types.String   `tfsdk:"acltype"`
+	Casesensitivity       types.String   `tfsdk:"casesensitivity"`
+	ShareType             types.String   `tfsdk:"share_type"`
+	SpecialSmallBlockSize sizeValue      `tfsdk:"special_small_block_size"`
+	Xattr                 types.String   `tfsdk:"xattr"`
+	Snapdev               types.String   `tfsdk:"snapdev"`
+	Managedby             types.String   `tfsdk:"managedby"`
+	CreateAncestors       types.Bool     `tfsdk:"create_ancestors"`
+	CloneFromSnapshot     types.String   `tfsdk:"clone_from_snapshot"`
+	Promote               types.Bool     `tfsdk:"promote"`
+	Mountpoint            types.String   `tfsdk:"mountpoint"`
+	Encrypted             types.Bool     `tfsdk:"encrypted"`
+	Encryption            types.Bool     `tfsdk:"encryption"`
+	InheritEncryption     types.Bool     `tfsdk:"inherit_encryption"`
+	EncryptionAlgorithm   types.String   `tfsdk:"encryption_algorithm"`
+	EncryptionGenerateKey types.Bool     `tfsdk:"encryption_generate_key"`
+	EncryptionKey         types.String   `tfsdk:"encryption_key_wo"`
+	EncryptionPassphrase  types.String   `tfsdk:"encryption_passphrase_wo"`
+	EncryptionPbkdf2iters types.Int64    `tfsdk:"encryption_pbkdf2iters"`
+	EncryptionWOVersion   types.Int64    `tfsdk:"encryption_wo_version"`
+	KeyFormat             types.String   `tfsdk:"key_format"`
+	Locked                types.Bool     `tfsdk:"locked"`
+	DeletionProtection    types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts              timeouts.Value `tfsdk:"timeouts"`
 }
 
 type zfsProperty struct {
@@ -81,35 +113,35 @@ func (p *zfsProperty) int64Value() (int64, bool) {
 	return 0, false
 }
 
-type poolDatasetUserProperties struct {
-	Comments *zfsProperty `json:"comments,omitempty"`
-}
-
 type poolDatasetResult struct {
-	ID              string                    `json:"id"`
-	Name            string                    `json:"name"`
-	Pool            string                    `json:"pool"`
-	Type            string                    `json:"type"`
-	Mountpoint      *string                   `json:"mountpoint"`
-	Encrypted       bool                      `json:"encrypted"`
-	UserProperties  poolDatasetUserProperties `json:"user_properties"`
-	Sync            zfsProperty               `json:"sync"`
-	Compression     zfsProperty               `json:"compression"`
-	Atime           zfsProperty               `json:"atime"`
-	Exec            zfsProperty               `json:"exec"`
-	Readonly        zfsProperty               `json:"readonly"`
-	Deduplication   zfsProperty               `json:"deduplication"`
-	Checksum        zfsProperty               `json:"checksum"`
-	Copies          zfsProperty               `json:"copies"`
-	Snapdir         zfsProperty               `json:"snapdir"`
-	Quota           zfsProperty               `json:"quota"`
-	Refquota        zfsProperty               `json:"refquota"`
-	Reservation     zfsProperty               `json:"reservation"`
-	Refreservation  zfsProperty               `json:"refreservation"`
-	Recordsize      zfsProperty               `json:"recordsize"`
-	Aclmode         zfsProperty               `json:"aclmode"`
-	Acltype         zfsProperty               `json:"acltype"`
-	Casesensitivity zfsProperty               `json:"casesensitivity"`
+	datasetPropertiesResult
+
+	ID                  string      `json:"id"`
+	Name                string      `json:"name"`
+	Pool                string      `json:"pool"`
+	Type                string      `json:"type"`
+	Mountpoint          *string     `json:"mountpoint"`
+	Encrypted           bool        `json:"encrypted"`
+	EncryptionRoot      *string     `json:"encryption_root"`
+	Locked              bool        `json:"locked"`
+	KeyFormat           zfsProperty `json:"key_format"`
+	EncryptionAlgorithm zfsProperty `json:"encryption_algorithm"`
+	Pbkdf2iters         zfsProperty `json:"pbkdf2iters"`
+	Atime               zfsProperty `json:"atime"`
+	Exec                zfsProperty `json:"exec"`
+	Snapdir             zfsProperty `json:"snapdir"`
+	Quota               zfsProperty `json:"quota"`
+	Refquota            zfsProperty `json:"refquota"`
+	Reservation         zfsProperty `json:"reservation"`
+	Refreservation      zfsProperty `json:"refreservation"`
+	Recordsize          zfsProperty `json:"recordsize"`
+	Aclmode             zfsProperty `json:"aclmode"`
+	Acltype             zfsProperty `json:"acltype"`
+	Casesensitivity     zfsProperty `json:"casesensitivity"`
+	SpecialSmallBlock   zfsProperty `json:"special_small_block_size"`
+	Xattr               zfsProperty `json:"xattr"`
+	Snapdev             zfsProperty `json:"snapdev"`
+	Managedby           zfsProperty `json:"managedby"`
 }
 
 func NewPoolDatasetResource() resource.Resource {
@@ -120,8 +152,9 @@ func (r *poolDatasetResource) Metadata(_ context.Context, req resource.MetadataR
 	resp.TypeName = req.ProviderTypeName + "_pool_dataset"
 }
 
-func (r *poolDatasetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *poolDatasetResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		MarkdownDescription: "Manages a TrueNAS ZFS dataset (filesystem type).\n\n" +
 			"~> **Note:** Only ZFS properties with a LOCAL source are stored in state. " +
 			"Properties that are inherited from a parent dataset or set to the ZFS default appear as null. " +
@@ -135,12 +168,14 @@ func (r *poolDatasetResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 			"name": schema.StringAttribute{
-				Description: "Full dataset path including pool, e.g. \"tank/data\". Cannot be changed after creation.",
-				Required:    true,
+				Description: "Full dataset path including pool, e.g. \"tank/data\", or a path relative to the provider's default_pool, e.g. \"./data\". " +
+					"Changing it within the same pool renames the dataset, with its children and snapshots, in place; the new parent must exist. Moving it to another pool replaces the dataset.",
+				Required: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"resolved_name": resolvedPathAttribute("name", "tank/data"),
 			"pool": schema.StringAttribute{
 				Description: "The pool name, extracted from the dataset path.",
 				Computed:    true,
@@ -155,66 +190,103 @@ func (r *poolDatasetResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"sync": schema.StringAttribute{
 				Description: "Sync mode: STANDARD, ALWAYS, or DISABLED. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("STANDARD", "ALWAYS", "DISABLED"),
+				},
 			},
 			"compression": schema.StringAttribute{
-				Description: "Compression algorithm: OFF, LZ4, GZIP, ZSTD, etc. Null means inherited from parent.",
+				Description: "Compression algorithm: OFF, LZ4, GZIP, ZSTD, etc. Checked against the algorithms supported by the server at plan time. Null means inherited from parent.",
 				Optional:    true,
 			},
 			"atime": schema.StringAttribute{
 				Description: "Access time updates: ON or OFF. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "OFF"),
+				},
 			},
 			"exec": schema.StringAttribute{
 				Description: "Allow execution of binaries: ON or OFF. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "OFF"),
+				},
 			},
 			"readonly": schema.StringAttribute{
 				Description: "Read-only mode: ON or OFF. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "OFF"),
+				},
 			},
 			"deduplication": schema.StringAttribute{
 				Description: "Deduplication: ON, VERIFY, or OFF. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "VERIFY", "OFF"),
+				},
 			},
 			"checksum": schema.StringAttribute{
-				Description: "Checksum algorithm: ON, FLETCHER2, FLETCHER4, SHA256, SHA512, SKEIN, BLAKE3. Null means inherited from parent.",
+				Description: "Checksum algorithm: ON, FLETCHER2, FLETCHER4, SHA256, SHA512, SKEIN, BLAKE3. Checked against the algorithms supported by the server at plan time. Null means inherited from parent.",
 				Optional:    true,
 			},
 			"copies": schema.Int64Attribute{
 				Description: "Number of data copies: 1, 2, or 3. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 3),
+				},
 			},
 			"snapdir": schema.StringAttribute{
 				Description: "Snapshot directory visibility: VISIBLE or HIDDEN. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("VISIBLE", "HIDDEN"),
+				},
 			},
-			"quota": schema.Int64Attribute{
-				Description: "Quota in bytes (minimum 1 GiB, or 0 to disable). Null means inherited from parent.",
+			"quota": schema.StringAttribute{
+				Description: "Quota (minimum 1 GiB, or 0 to disable), as bytes or with a unit such as \"50GiB\". Null means inherited from parent.",
 				Optional:    true,
+				CustomType:  sizeType{},
+				Validators: []validator.String{
+					sizeZeroOrAtLeast(minDatasetQuota),
+				},
 			},
-			"refquota": schema.Int64Attribute{
-				Description: "Reference quota in bytes (minimum 1 GiB, or 0 to disable). Null means inherited from parent.",
+			"refquota": schema.StringAttribute{
+				Description: "Reference quota (minimum 1 GiB, or 0 to disable), as bytes or with a unit such as \"50GiB\". Null means inherited from parent.",
 				Optional:    true,
+				CustomType:  sizeType{},
+				Validators: []validator.String{
+					sizeZeroOrAtLeast(minDatasetQuota),
+				},
 			},
-			"reservation": schema.Int64Attribute{
-				Description: "Reservation in bytes. Null means inherited from parent.",
+			"reservation": schema.StringAttribute{
+				Description: "Reservation, as bytes or with a unit such as \"10GiB\". Null means inherited from parent.",
 				Optional:    true,
+				CustomType:  sizeType{},
 			},
-			"refreservation": schema.Int64Attribute{
-				Description: "Reference reservation in bytes. Null means inherited from parent.",
+			"refreservation": schema.StringAttribute{
+				Description: "Reference reservation, as bytes or with a unit such as \"10GiB\". Null means inherited from parent.",
 				Optional:    true,
+				CustomType:  sizeType{},
 			},
 			"recordsize": schema.StringAttribute{
-				Description: "Record size, e.g. \"128K\", \"1M\". Null means inherited from parent.",
+				Description: "Record size, e.g. \"128K\", \"1M\". Checked against the sizes supported by the server at plan time. Null means inherited from parent.",
 				Optional:    true,
 			},
 			"aclmode": schema.StringAttribute{
 				Description: "ACL mode: PASSTHROUGH, RESTRICTED, or DISCARD. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("PASSTHROUGH", "RESTRICTED", "DISCARD"),
+				},
 			},
 			"acltype": schema.StringAttribute{
 				Description: "ACL type: OFF, NFSV4, or POSIX. Cannot be changed after creation. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("OFF", "NFSV4", "POSIX"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -222,10 +294,51 @@ func (r *poolDatasetResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"casesensitivity": schema.StringAttribute{
 				Description: "Case sensitivity: SENSITIVE or INSENSITIVE. Cannot be changed after creation. Null means inherited from parent.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("SENSITIVE", "INSENSITIVE"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"share_type": schema.StringAttribute{
+				Description: "Share type preset the dataset is created for: GENERIC, SMB, NFS, MULTIPROTOCOL or APPS. " +
+					"Presets other than GENERIC choose acltype, aclmode and casesensitivity, which then cannot be set, and the values they choose appear as null. " +
+					"Only used during creation; changing it replaces the dataset.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("GENERIC", "SMB", "NFS", "MULTIPROTOCOL", "APPS"),
+				},
+				PlanModifiers: []planmodifier.String{
+					replaceIfStateSetString(),
+				},
+			},
+			"special_small_block_size": schema.StringAttribute{
+				Description: "Blocks up to this size are stored on the special vdev of the pool, as bytes or with a unit such as \"64K\": 0 to disable, or a power of two no larger than recordsize. Null means inherited from parent.",
+				Optional:    true,
+				CustomType:  sizeType{},
+			},
+			"xattr": schema.StringAttribute{
+				Description: "Extended attribute storage: ON (in hidden directories) or SA (in system attributes). Null means inherited from parent.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "SA"),
+				},
+			},
+			"snapdev": schema.StringAttribute{
+				Description: "Visibility of the snapshots of zvols below this dataset as devices: HIDDEN or VISIBLE. Null means inherited from parent.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("HIDDEN", "VISIBLE"),
+				},
+			},
+			"managedby": schema.StringAttribute{
+				Description: "Name of the application managing the dataset, shown in the TrueNAS UI. Null means inherited from parent.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
 			"create_ancestors": schema.BoolAttribute{
 				Description: "Create ancestor datasets if they don't exist. Only used during creation, not stored in state.",
 				Optional:    true,
@@ -233,6 +346,25 @@ func (r *poolDatasetResource) Schema(_ context.Context, _ resource.SchemaRequest
 					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"clone_from_snapshot": schema.StringAttribute{
+				Description: "Create the dataset as a clone of this snapshot (e.g. \"tank/golden@base\") instead of as an empty dataset, then apply the other properties. " +
+					"acltype, casesensitivity and share_type are inherited from the snapshot and cannot be set. Only used during creation; changing it replaces the dataset.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^@]+@[^@/]+$`), "must be a full snapshot name, dataset@name"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"promote": schema.BoolAttribute{
+				Description: "Promote the clone, so that it no longer depends on clone_from_snapshot and the origin dataset can be destroyed. " +
+					"Promotion moves the origin snapshot, and the older snapshots of the origin, to this dataset. " +
+					"Can be enabled after creation; a promotion cannot be undone, so disabling it has no effect. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
 			"mountpoint": schema.StringAttribute{
 				Description: "The mount point of the dataset.",
 				Computed:    true,
@@ -241,8 +373,36 @@ func (r *poolDatasetResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "Whether the dataset is encrypted.",
 				Computed:    true,
 			},
+			"deletion_protection": deletionProtectionAttribute("dataset"),
+			"user_properties":     userPropertiesAttribute("dataset"),
+			"ownership_tag":       ownershipTagAttribute("dataset", "in its "+ownershipProperty+" ZFS user property"),
+			"timeouts":            timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
+	maps.Copy(resp.Schema.Attributes, datasetEncryptionAttributes())
+}
+
+func (r *poolDatasetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config poolDatasetResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateDatasetEncryption(&config, &resp.Diagnostics)
+
+	if isShareTypePreset(config.ShareType) {
+		preset := []struct {
+			name  string
+			value types.String
+		}{{"acltype", config.Acltype}, {"aclmode", config.Aclmode}, {"casesensitivity", config.Casesensitivity}}
+		for _, p := range preset {
+			if !p.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(p.name), "Property Set By Share Type",
+					fmt.Sprintf("%s cannot be set with share_type %s, which chooses it.", p.name, config.ShareType.ValueString()))
+			}
+		}
+	}
 }
 
 func (r *poolDatasetResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -250,16 +410,64 @@ func (r *poolDatasetResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
+	r.provider = data
+}
+
+// ModifyPlan checks properties whose allowed values depend on the ZFS version of the
+// TrueNAS system against the server's choices methods.
+func (r *poolDatasetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state poolDatasetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateServerChoice(ctx, r.client, "pool.dataset.compression_choices", []any{}, path.Root("compression"), plan.Compression, state.Compression, &resp.Diagnostics)
+	validateServerChoice(ctx, r.client, "pool.dataset.checksum_choices", []any{}, path.Root("checksum"), plan.Checksum, state.Checksum, &resp.Diagnostics)
+	validateServerChoice(ctx, r.client, "pool.dataset.recordsize_choices", []any{}, path.Root("recordsize"), plan.Recordsize, state.Recordsize, &resp.Diagnostics)
+
+	checkRelativePath(r.provider, path.Root("name"), plan.Name, &resp.Diagnostics)
+	ignoreEquivalentReplace(r.provider, datasetNamePath, path.Root("name"), plan.Name, state.Name, resp)
+	planResolvedPath(ctx, r.provider, datasetNamePath, path.Root("resolved_name"), plan.Name, resp)
+	r.planRename(ctx, &plan, &state, resp)
+
+	if plan.CloneFromSnapshot.IsNull() {
+		if plan.Promote.ValueBool() {
+			resp.Diagnostics.AddAttributeError(path.Root("promote"), "Not a Clone",
+				"promote can only be set when clone_from_snapshot is set.")
+		}
+	} else {
+		inherited := []struct {
+			name  string
+			value types.String
+		}{{"acltype", plan.Acltype}, {"casesensitivity", plan.Casesensitivity}, {"share_type", plan.ShareType}}
+		for _, p := range inherited {
+			if !p.value.IsNull() && req.State.Raw.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(p.name), "Property Inherited From Snapshot",
+					fmt.Sprintf("%s cannot be set on a clone; it is inherited from %s.", p.name, plan.CloneFromSnapshot.ValueString()))
+			}
+		}
+	}
+
+	planDatasetEncryptionChange(ctx, &plan, &state, resp)
+	planOwnershipTag(ctx, r.provider, req, resp)
 }
 
 func (r *poolDatasetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -269,33 +477,58 @@ func (r *poolDatasetResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if !plan.CloneFromSnapshot.IsNull() {
+		// A clone whose properties could not be applied is still saved, so that
+		// Terraform marks it tainted instead of losing track of it.
+		if r.createClone(ctx, &plan, &resp.Diagnostics) {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		}
+		return
+	}
+
 	params := map[string]any{
-		"name": plan.Name.ValueString(),
+		"name": resolvePoolPath(r.provider.defaultPool, datasetNamePath, plan.Name.ValueString()),
 		"type": "FILESYSTEM",
 	}
 
-	setStringParam(params, "comments", plan.Comments)
-	setStringParam(params, "sync", plan.Sync)
-	setStringParam(params, "compression", plan.Compression)
+	setDatasetPropertiesCreateParams(params, &plan.datasetPropertiesModel)
 	setStringParam(params, "atime", plan.Atime)
 	setStringParam(params, "exec", plan.Exec)
-	setStringParam(params, "readonly", plan.Readonly)
-	setStringParam(params, "deduplication", plan.Deduplication)
-	setStringParam(params, "checksum", plan.Checksum)
-	setInt64Param(params, "copies", plan.Copies)
 	setStringParam(params, "snapdir", plan.Snapdir)
-	setInt64Param(params, "quota", plan.Quota)
-	setInt64Param(params, "refquota", plan.Refquota)
-	setInt64Param(params, "reservation", plan.Reservation)
-	setInt64Param(params, "refreservation", plan.Refreservation)
+	setSizeParam(params, "quota", plan.Quota)
+	setSizeParam(params, "refquota", plan.Refquota)
+	setSizeParam(params, "reservation", plan.Reservation)
+	setSizeParam(params, "refreservation", plan.Refreservation)
 	setStringParam(params, "recordsize", plan.Recordsize)
 	setStringParam(params, "aclmode", plan.Aclmode)
 	setStringParam(params, "acltype", plan.Acltype)
 	setStringParam(params, "casesensitivity", plan.Casesensitivity)
+	setSizeParam(params, "special_small_block_size", plan.SpecialSmallBlockSize)
+	setStringParam(params, "xattr", plan.Xattr)
+	setStringParam(params, "snapdev", plan.Snapdev)
+	setStringParam(params, "managedby", plan.Managedby)
+	if !plan.ShareType.IsNull() {
+		params["share_type"] = plan.ShareType.ValueString()
+	}
 
 	if !plan.CreateAncestors.IsNull() && plan.CreateAncestors.ValueBool() {
 		params["create_ancestors"] = true
 	}
+	var config poolDatasetResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	setDatasetEncryptionParams(params, &plan, &config)
 
 	var result poolDatasetResult
 	err := r.client.Call(ctx, "pool.dataset.create", []any{params}, &result)
@@ -304,7 +537,16 @@ func (r *poolDatasetResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	populateDatasetState(&plan, &result)
+	if plan.Locked.ValueBool() {
+		if err := lockDataset(ctx, r.client, result.ID); err != nil {
+			resp.Diagnostics.AddError("Error Locking Pool Dataset", err.Error())
+		} else if err := r.client.Call(ctx, "pool.dataset.get_instance", []any{result.ID}, &result); err != nil {
+			resp.Diagnostics.AddError("Error Reading Pool Dataset", err.Error())
+		}
+	}
+
+	// The dataset is saved even if it could not be locked, so that it is not orphaned.
+	populateDatasetState(&plan, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -318,7 +560,7 @@ func (r *poolDatasetResource) Read(ctx context.Context, req resource.ReadRequest
 	var result poolDatasetResult
 	err := r.client.Call(ctx, "pool.dataset.get_instance", []any{state.ID.ValueString()}, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -326,7 +568,11 @@ func (r *poolDatasetResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	populateDatasetState(&state, &result)
+	populateDatasetState(&state, &result, r.provider.defaultPool)
+	state.DeletionProtection = deletionProtectionValue(state.DeletionProtection)
+	if state.Promote.IsNull() {
+		state.Promote = types.BoolValue(false)
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -337,52 +583,100 @@ func (r *poolDatasetResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var state poolDatasetResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	params := map[string]any{}
+	// Write-only secrets are only available in the configuration.
+	var config poolDatasetResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// comments is a ZFS user property — handle explicit set and explicit unset (inherit)
-	if !plan.Comments.IsNull() {
-		params["comments"] = plan.Comments.ValueString()
-	} else if !state.Comments.IsNull() {
-		// Transition from a previously set comment to null: clear/unset so it can inherit
-		params["comments"] = nil
+	id := state.ID.ValueString()
+	if newID := plan.ID.ValueString(); newID != id {
+		renamed, err := datasetRenamedWithAncestor(ctx, r.client, id, newID)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Renaming Pool Dataset", err.Error())
+			return
+		}
+		// Renaming a parent dataset earlier in the same apply already moved this one.
+		if !renamed {
+			err := r.client.Call(ctx, "pool.dataset.rename", []any{id, map[string]any{"new_name": newID}}, nil)
+			if err != nil {
+				resp.Diagnostics.AddError("Error Renaming Pool Dataset", err.Error())
+				return
+			}
+		}
+		// Keep track of the dataset under its new name should a later step fail.
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), newID)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), plan.Name)...)
+		id = newID
 	}
-	setStringParamOrInherit(params, "sync", plan.Sync)
-	setStringParamOrInherit(params, "compression", plan.Compression)
-	setStringParamOrInherit(params, "atime", plan.Atime)
-	setStringParamOrInherit(params, "exec", plan.Exec)
-	setStringParamOrInherit(params, "readonly", plan.Readonly)
-	setStringParamOrInherit(params, "deduplication", plan.Deduplication)
-	setStringParamOrInherit(params, "checksum", plan.Checksum)
-	// copies accepts int or "INHERIT"
-	if plan.Copies.IsNull() {
-		params["copies"] = "INHERIT"
-	} else {
-		params["copies"] = plan.Copies.ValueInt64()
+
+	unlock := !plan.Locked.IsUnknown() && !plan.Locked.ValueBool() && state.Locked.ValueBool()
+	lock := plan.Locked.ValueBool() && !state.Locked.ValueBool()
+
+	if unlock {
+		if err := unlockDataset(ctx, r.client, id, &config); err != nil {
+			resp.Diagnostics.AddError("Error Unlocking Pool Dataset", err.Error())
+			return
+		}
 	}
-	setStringParamOrInherit(params, "snapdir", plan.Snapdir)
-	// quota, refquota, reservation, refreservation: omit when null to leave unchanged.
-	// The API accepts nil for quota/refquota but sets them to 0 (LOCAL), not inherited.
-	setInt64Param(params, "quota", plan.Quota)
-	setInt64Param(params, "refquota", plan.Refquota)
-	setInt64Param(params, "reservation", plan.Reservation)
-	setInt64Param(params, "refreservation", plan.Refreservation)
-	setStringParamOrInherit(params, "recordsize", plan.Recordsize)
-	setStringParamOrInherit(params, "aclmode", plan.Aclmode)
+
+	if state.Encryption.ValueBool() && !plan.Encryption.ValueBool() {
+		if err := inheritDatasetEncryption(ctx, r.client, id); err != nil {
+			resp.Diagnostics.AddError("Error Inheriting Parent Encryption", err.Error())
+			return
+		}
+	} else if datasetKeyChanged(&plan, &state) {
+		if err := changeDatasetKey(ctx, r.client, id, &plan, &config); err != nil {
+			resp.Diagnostics.AddError("Error Changing Dataset Key", err.Error())
+			return
+		}
+	}
+
+	if plan.Promote.ValueBool() && !state.Promote.ValueBool() {
+		err := r.client.Call(ctx, "pool.dataset.promote", []any{id}, nil)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Promoting Pool Dataset", err.Error())
+			return
+		}
+	}
+
+	params := datasetUpdateParams(&plan, &state)
 
 	var result poolDatasetResult
-	err := r.client.Call(ctx, "pool.dataset.update", []any{state.ID.ValueString(), params}, &result)
+	err := r.client.Call(ctx, "pool.dataset.update", []any{id, params}, &result)
 	if err != nil {
 		resp.Diagnostics.AddError("Error Updating Pool Dataset", err.Error())
 		return
 	}
 
-	populateDatasetState(&plan, &result)
+	if lock {
+		if err := lockDataset(ctx, r.client, id); err != nil {
+			resp.Diagnostics.AddError("Error Locking Pool Dataset", err.Error())
+			return
+		}
+		if err := r.client.Call(ctx, "pool.dataset.get_instance", []any{id}, &result); err != nil {
+			resp.Diagnostics.AddError("Error Reading Pool Dataset", err.Error())
+			return
+		}
+	}
+
+	populateDatasetState(&plan, &result, r.provider.defaultPool)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -393,6 +687,19 @@ func (r *poolDatasetResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	if !checkDeletionProtection("dataset", state.ID.ValueString(), state.DeletionProtection, &resp.Diagnostics) {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	deleteOpts := map[string]any{
 		"recursive": false,
 		"force":     false,
@@ -405,10 +712,137 @@ func (r *poolDatasetResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 }
 
+// createClone creates the dataset of plan by cloning its clone_from_snapshot, applies
+// the configured properties as an update would, and promotes it if requested. It
+// returns whether the clone exists, in which case plan is populated from it; errors
+// are added to diags.
+func (r *poolDatasetResource) createClone(ctx context.Context, plan *poolDatasetResourceModel, diags *diag.Diagnostics) bool {
+	name := resolvePoolPath(r.provider.defaultPool, datasetNamePath, plan.Name.ValueString())
+	cloneParams := map[string]any{
+		"snapshot":    plan.CloneFromSnapshot.ValueString(),
+		"dataset_dst": name,
+	}
+	if err := r.client.Call(ctx, "pool.snapshot.clone", []any{cloneParams}, nil); err != nil {
+		diags.AddError("Error Cloning Snapshot", err.Error())
+		return false
+	}
+
+	// The clone starts with the properties of the snapshot; the empty model stands for
+	// "nothing set locally".
+	var result poolDatasetResult
+	err := r.client.Call(ctx, "pool.dataset.update", []any{name, datasetUpdateParams(plan, &poolDatasetResourceModel{})}, &result)
+	if err != nil {
+		diags.AddError("Error Updating Cloned Pool Dataset",
+			fmt.Sprintf("Dataset %s was cloned from %s, but its properties could not be set: %s", name, plan.CloneFromSnapshot.ValueString(), err.Error()))
+	} else if plan.Promote.ValueBool() {
+		if err := r.client.Call(ctx, "pool.dataset.promote", []any{name}, nil); err != nil {
+			diags.AddError("Error Promoting Pool Dataset", err.Error())
+			plan.Promote = types.BoolValue(false)
+		}
+	}
+
+	if diags.HasError() {
+		if err := r.client.Call(ctx, "pool.dataset.get_instance", []any{name}, &result); err != nil {
+			diags.AddError("Error Reading Cloned Pool Dataset", err.Error())
+			return false
+		}
+	}
+
+	populateDatasetState(plan, &result, r.provider.defaultPool)
+	return true
+}
+
+// planRename turns a change of name within the same pool into an in-place rename,
+// and warns about the objects that refer to the dataset by its old path.
+func (r *poolDatasetResource) planRename(ctx context.Context, plan, state *poolDatasetResourceModel, resp *resource.ModifyPlanResponse) {
+	if plan.Name.IsUnknown() || state.ID.IsNull() {
+		return
+	}
+	oldName := state.ID.ValueString()
+	newName := resolvePoolPath(providerDefaultPool(r.provider), datasetNamePath, plan.Name.ValueString())
+	if newName == oldName || datasetPool(newName) != datasetPool(oldName) {
+		return
+	}
+
+	resp.RequiresReplace = slices.DeleteFunc(resp.RequiresReplace, func(p path.Path) bool { return p.Equal(path.Root("name")) })
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), newName)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("mountpoint"), types.StringUnknown())...)
+
+	if r.client == nil {
+		return
+	}
+	var attachments []struct {
+		Type        string   `json:"type"`
+		Attachments []string `json:"attachments"`
+	}
+	if err := r.client.Call(ctx, "pool.dataset.attachments", []any{oldName}, &attachments); err != nil {
+		tflog.Warn(ctx, "Unable to query dataset attachments, skipping the dependent objects warning", map[string]any{
+			"dataset": oldName,
+			"error":   err.Error(),
+		})
+		return
+	}
+	var dependents []string
+	for _, a := range attachments {
+		for _, name := range a.Attachments {
+			dependents = append(dependents, fmt.Sprintf("%s %q", a.Type, name))
+		}
+	}
+	if len(dependents) > 0 {
+		resp.Diagnostics.AddAttributeWarning(path.Root("name"), "Dataset Has Dependent Objects",
+			fmt.Sprintf("Dataset %s will be renamed to %s in place. These objects refer to it by its old path and must be updated to the new one: %s. "+
+				"Objects configured from this dataset's name or mountpoint are updated in the same apply.", oldName, newName, strings.Join(dependents, ", ")))
+	}
+}
+
+// datasetRenamedWithAncestor reports whether a dataset was already moved from oldName
+// to newName by the rename of one of its ancestors: the old path no longer exists and
+// the new one does.
+func datasetRenamedWithAncestor(ctx context.Context, c *client.Client, oldName, newName string) (bool, error) {
+	var result poolDatasetResult
+	if err := c.Call(ctx, "pool.dataset.get_instance", []any{oldName}, &result); err == nil {
+		return false, nil
+	} else if !isNotFound(err) {
+		return false, err
+	}
+	if err := c.Call(ctx, "pool.dataset.get_instance", []any{newName}, &result); err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// datasetPool returns the pool of a dataset name.
+func datasetPool(name string) string {
+	pool, _, _ := strings.Cut(name, "/")
+	return pool
+}
+
 func (r *poolDatasetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The ownership check reads the object, which is skipped before the provider is
+	// configured.
+	if r.client != nil {
+		var result poolDatasetResult
+		if err := r.client.Call(ctx, "pool.dataset.get_instance", []any{req.ID}, &result); err == nil && result.UserProperties.Workspace != nil {
+			checkImportOwnership(r.provider, "Pool Dataset", result.Name, readStringProperty(result.UserProperties.Workspace).ValueString(), &resp.Diagnostics)
+		}
+	}
+
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+// UpgradeState migrates state written by earlier schema versions.
+//
+// Version 1 changed quota, refquota, reservation and refreservation from numbers to
+// strings accepting units.
+func (r *poolDatasetResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return stateUpgraders(
+		numbersToStrings("quota", "refquota", "reservation", "refreservation"),
+	)
+}
+
 // setStringParam sets a string field in params if the Terraform value is non-null,
 // or sets INHERIT if null. Works for both create and update.
 func setStringParam(params map[string]any, key string, val types.String) {
@@ -427,16 +861,56 @@ func setInt64Param(params map[string]any, key string, val types.Int64) {
 	}
 }
 
+// setSizeParam sets a size field in params, in bytes, only if the Terraform value is non-null.
+// Like integers, null sizes are omitted rather than sent as "INHERIT".
+func setSizeParam(params map[string]any, key string, val sizeValue) {
+	if !val.IsNull() {
+		params[key] = val.ValueBytes()
+	}
+}
+
 // setStringParamOrInherit is an alias for setStringParam (same behavior for update).
 func setStringParamOrInherit(params map[string]any, key string, val types.String) {
 	setStringParam(params, key, val)
 }
 
+// datasetUpdateParams returns the pool.dataset.update parameters that apply the ZFS
+// properties of plan to a dataset whose properties are those of state.
+func datasetUpdateParams(plan, state *poolDatasetResourceModel) map[string]any {
+	params := map[string]any{}
+
+	setDatasetPropertiesUpdateParams(params, &plan.datasetPropertiesModel, &state.datasetPropertiesModel)
+	setStringParamOrInherit(params, "atime", plan.Atime)
+	setStringParamOrInherit(params, "exec", plan.Exec)
+	setStringParamOrInherit(params, "snapdir", plan.Snapdir)
+	// quota, refquota, reservation, refreservation: omit when null to leave unchanged.
+	// The API accepts nil for quota/refquota but sets them to 0 (LOCAL), not inherited.
+	setSizeParam(params, "quota", plan.Quota)
+	setSizeParam(params, "refquota", plan.Refquota)
+	setSizeParam(params, "reservation", plan.Reservation)
+	setSizeParam(params, "refreservation", plan.Refreservation)
+	setStringParamOrInherit(params, "recordsize", plan.Recordsize)
+	setStringParamOrInherit(params, "aclmode", plan.Aclmode)
+	// special_small_block_size accepts bytes or "INHERIT"
+	if plan.SpecialSmallBlockSize.IsNull() {
+		params["special_small_block_size"] = "INHERIT"
+	} else {
+		params["special_small_block_size"] = plan.SpecialSmallBlockSize.ValueBytes()
+	}
+	setStringParamOrInherit(params, "xattr", plan.Xattr)
+	setStringParamOrInherit(params, "snapdev", plan.Snapdev)
+	setStringParamOrInherit(params, "managedby", plan.Managedby)
+
+	return params
+}
+
 // populateDatasetState updates the Terraform resource model from a TrueNAS API result.
 // For ZFS properties, only LOCAL-sourced values are stored; inherited/default values become null.
-func populateDatasetState(model *poolDatasetResourceModel, result *poolDatasetResult) {
+// A name configured relative to defaultPool is kept as configured.
+func populateDatasetState(model *poolDatasetResourceModel, result *poolDatasetResult, defaultPool string) {
 	model.ID = types.StringValue(result.ID)
-	model.Name = types.StringValue(result.Name)
+	model.Name = keepRelativePath(defaultPool, datasetNamePath, model.Name, result.Name)
+	model.ResolvedName = types.StringValue(result.Name)
 	model.Pool = types.StringValue(result.Pool)
 
 	if result.Mountpoint != nil {
@@ -444,30 +918,47 @@ func populateDatasetState(model *poolDatasetResourceModel, result *poolDatasetRe
 	} else {
 		model.Mountpoint = types.StringNull()
 	}
-	model.Encrypted = types.BoolValue(result.Encrypted)
+	populateDatasetEncryption(model, result)
 
-	if result.UserProperties.Comments != nil {
-		model.Comments = readStringProperty(result.UserProperties.Comments)
-	} else {
-		model.Comments = types.StringNull()
-	}
-	model.Sync = readStringProperty(&result.Sync)
-	model.Compression = readStringProperty(&result.Compression)
+	aclmode, acltype, casesensitivity := model.Aclmode, model.Acltype, model.Casesensitivity
+	populateDatasetProperties(&model.datasetPropertiesModel, &result.datasetPropertiesResult)
 	model.Atime = readStringProperty(&result.Atime)
 	model.Exec = readStringProperty(&result.Exec)
-	model.Readonly = readStringProperty(&result.Readonly)
-	model.Deduplication = readStringProperty(&result.Deduplication)
-	model.Checksum = readStringProperty(&result.Checksum)
-	model.Copies = readInt64Property(&result.Copies)
 	model.Snapdir = readStringProperty(&result.Snapdir)
-	model.Quota = readInt64Property(&result.Quota)
-	model.Refquota = readInt64Property(&result.Refquota)
-	model.Reservation = readInt64Property(&result.Reservation)
-	model.Refreservation = readInt64Property(&result.Refreservation)
+	model.Quota = readSizeProperty(&result.Quota)
+	model.Refquota = readSizeProperty(&result.Refquota)
+	model.Reservation = readSizeProperty(&result.Reservation)
+	model.Refreservation = readSizeProperty(&result.Refreservation)
 	model.Recordsize = readStringProperty(&result.Recordsize)
 	model.Aclmode = readStringProperty(&result.Aclmode)
 	model.Acltype = readStringProperty(&result.Acltype)
 	model.Casesensitivity = readStringProperty(&result.Casesensitivity)
+	model.SpecialSmallBlockSize = readSizeProperty(&result.SpecialSmallBlock)
+	model.Xattr = readStringProperty(&result.Xattr)
+	model.Snapdev = readStringProperty(&result.Snapdev)
+	model.Managedby = readStringProperty(&result.Managedby)
+
+	// The properties chosen by a share_type preset stay null unless configured.
+	if isShareTypePreset(model.ShareType) {
+		model.Aclmode = keepUnsetPresetProperty(aclmode, model.Aclmode)
+		model.Acltype = keepUnsetPresetProperty(acltype, model.Acltype)
+		model.Casesensitivity = keepUnsetPresetProperty(casesensitivity, model.Casesensitivity)
+	}
+}
+
+// isShareTypePreset reports whether shareType chooses acltype, aclmode and
+// casesensitivity.
+func isShareTypePreset(shareType types.String) bool {
+	return !shareType.IsNull() && !shareType.IsUnknown() && shareType.ValueString() != "GENERIC"
+}
+
+// keepUnsetPresetProperty returns null when the prior value of a property chosen by a
+// share_type preset was null, and the value read otherwise.
+func keepUnsetPresetProperty(prior, read types.String) types.String {
+	if prior.IsNull() {
+		return prior
+	}
+	return read
 }
 
 func readStringProperty(prop *zfsProperty) types.String {
@@ -477,6 +968,15 @@ func readStringProperty(prop *zfsProperty) types.String {
 	return types.StringNull()
 }
 
+// readUserProperty is readStringProperty for a user property, which is absent (nil)
+// when it is not set anywhere.
+func readUserProperty(prop *zfsProperty) types.String {
+	if prop == nil {
+		return types.StringNull()
+	}
+	return readStringProperty(prop)
+}
+
 func readInt64Property(prop *zfsProperty) types.Int64 {
 	if prop.isLocal() {
 		if n, ok := prop.int64Value(); ok {
@@ -485,3 +985,12 @@ func readInt64Property(prop *zfsProperty) types.Int64 {
 	}
 	return types.Int64Null()
 }
+
+func readSizeProperty(prop *zfsProperty) sizeValue {
+	if prop.isLocal() {
+		if n, ok := prop.int64Value(); ok {
+			return sizeBytes(n)
+		}
+	}
+	return sizeNull()
+}
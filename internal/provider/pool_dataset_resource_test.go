@@ -3,9 +3,13 @@ package provider
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func testAccPoolName() string {
@@ -36,7 +40,7 @@ func TestAccPoolDatasetResource_basic(t *testing.T) {
 				ResourceName:            "truenas_pool_dataset.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"create_ancestors"},
+				ImportStateVerifyIgnore: []string{"create_ancestors", "deletion_protection"},
 			},
 		},
 	})
@@ -108,10 +112,438 @@ func TestAccPoolDatasetResource_nested(t *testing.T) {
 	})
 }
 
+func TestAccPoolDatasetResource_sizes(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-sizes"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigWithQuota(dsName, "2GiB"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "quota", "2GiB"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigWithQuota(dsName, "1.5T"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "quota", "1.5T"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_invalidValues(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-invalid"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPoolDatasetResourceConfigWithCompression(dsName, "LZ5"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`"LZ5" is not supported by this TrueNAS system`),
+			},
+			{
+				Config:      testAccPoolDatasetResourceConfigWithQuota(dsName, "100"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Attribute quota size must be 0 or at least 1GiB`),
+			},
+			{
+				Config:      testAccPoolDatasetResourceConfigWithShareTypeAcl(dsName),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`acltype cannot be set with share_type SMB`),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_deletionProtection(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-protected"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigProtected(dsName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testAccPoolDatasetResourceConfigProtected(dsName),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`deletion_protection is enabled`),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfig(dsName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_ownershipTag(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-owned"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigOwned(dsName, "tf-acc-test"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "ownership_tag", "tf-acc-test"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigOwned(dsName, "tf-acc-test/other"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "ownership_tag", "tf-acc-test/other"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_relativeName(t *testing.T) {
+	pool := testAccPoolName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigRelative(pool, "./tf-acc-test-relative"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Terraform requires name to be stored as configured; resolved_name is fully qualified.
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "name", "./tf-acc-test-relative"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "resolved_name", pool+"/tf-acc-test-relative"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "id", pool+"/tf-acc-test-relative"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "pool", pool),
+				),
+			},
+			{
+				// Switching to the absolute name of the same dataset updates it in place.
+				Config: testAccPoolDatasetResourceConfigRelative(pool, pool+"/tf-acc-test-relative"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "name", pool+"/tf-acc-test-relative"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "resolved_name", pool+"/tf-acc-test-relative"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_clone(t *testing.T) {
+	pool := testAccPoolName()
+	golden := pool + "/tf-acc-test-golden"
+	clone := pool + "/tf-acc-test-clone"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigClone(golden, clone),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.clone", "id", clone),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.clone", "clone_from_snapshot", golden+"@base"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.clone", "compression", "ZSTD"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.clone", "promote", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_encryption(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-encrypted"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		// Write-only attributes require Terraform 1.11.
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigEncrypted(dsName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "encryption", "true"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "encrypted", "true"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "key_format", "PASSPHRASE"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "encryption_algorithm", "AES-256-GCM"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "locked", "false"),
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "encryption_passphrase_wo"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigEncrypted(dsName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "locked", "true"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigEncrypted(dsName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "locked", "false"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigRotated(dsName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "key_format", "HEX"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "encryption_wo_version", "2"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "locked", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_userProperties(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-userprops"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigUserProperties(dsName, `{
+    "com.example:backup" = "daily"
+    "com.example:owner"  = "ops"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "user_properties.%", "2"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "user_properties.com.example:backup", "daily"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.child", "user_properties.%", "0"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigUserProperties(dsName, `{
+    "com.example:backup" = "weekly"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "user_properties.%", "1"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "user_properties.com.example:backup", "weekly"),
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "user_properties.com.example:owner"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigUserProperties(dsName, "null"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "user_properties.%"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_properties(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-properties"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigProperties(dsName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "share_type", "SMB"),
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "acltype"),
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "casesensitivity"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "xattr", "SA"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "snapdev", "VISIBLE"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "managedby", "backup-tool"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.child", "sync", "ALWAYS"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigWithShareType(dsName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "xattr"),
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "snapdev"),
+					resource.TestCheckNoResourceAttr("truenas_pool_dataset.test", "managedby"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolDatasetResource_rename(t *testing.T) {
+	parent := testAccPoolName() + "/tf-acc-test-rename"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolDatasetResourceConfigRename(parent, "before"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "id", parent+"/before"),
+					resource.TestCheckResourceAttr("truenas_nfs_share.test", "path", "/mnt/"+parent+"/before"),
+				),
+			},
+			{
+				Config: testAccPoolDatasetResourceConfigRename(parent, "after"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("truenas_pool_dataset.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectResourceAction("truenas_nfs_share.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "id", parent+"/after"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "name", parent+"/after"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "mountpoint", "/mnt/"+parent+"/after"),
+					resource.TestCheckResourceAttr("truenas_nfs_share.test", "path", "/mnt/"+parent+"/after"),
+				),
+			},
+			// Renaming the parent moves the child with it; the child's own rename is skipped.
+			{
+				Config: testAccPoolDatasetResourceConfigRename(parent+"-renamed", "after"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("truenas_pool_dataset.parent", plancheck.ResourceActionUpdate),
+						plancheck.ExpectResourceAction("truenas_pool_dataset.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectResourceAction("truenas_nfs_share.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_dataset.parent", "id", parent+"-renamed"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "id", parent+"-renamed/after"),
+					resource.TestCheckResourceAttr("truenas_pool_dataset.test", "mountpoint", "/mnt/"+parent+"-renamed/after"),
+					resource.TestCheckResourceAttr("truenas_nfs_share.test", "path", "/mnt/"+parent+"-renamed/after"),
+				),
+			},
+		},
+	})
+}
+
+func TestDatasetUpdateParams(t *testing.T) {
+	state := poolDatasetResourceModel{
+		datasetPropertiesModel: datasetPropertiesModel{
+			Comments: types.StringValue("old"),
+			Sync:     types.StringValue("ALWAYS"),
+			Copies:   types.Int64Value(2),
+		},
+		Quota:                 sizeBytes(10 << 30),
+		SpecialSmallBlockSize: sizeBytes(64 << 10),
+		Xattr:                 types.StringValue("SA"),
+		Snapdev:               types.StringValue("VISIBLE"),
+		Managedby:             types.StringValue("backup-tool"),
+	}
+
+	tests := []struct {
+		name    string
+		plan    poolDatasetResourceModel
+		want    map[string]any
+		omitted []string
+	}{
+		{
+			name: "null inherits",
+			plan: poolDatasetResourceModel{},
+			want: map[string]any{
+				"comments":                 nil,
+				"sync":                     "INHERIT",
+				"copies":                   "INHERIT",
+				"special_small_block_size": "INHERIT",
+				"xattr":                    "INHERIT",
+				"snapdev":                  "INHERIT",
+				"managedby":                "INHERIT",
+			},
+			omitted: []string{"quota", "user_properties_update"},
+		},
+		{
+			name: "set",
+			plan: poolDatasetResourceModel{
+				datasetPropertiesModel: datasetPropertiesModel{
+					Comments: types.StringValue("new"),
+					Sync:     types.StringValue("DISABLED"),
+					Copies:   types.Int64Value(3),
+				},
+				Quota:                 sizeBytes(20 << 30),
+				SpecialSmallBlockSize: sizeBytes(128 << 10),
+				Xattr:                 types.StringValue("ON"),
+				Snapdev:               types.StringValue("HIDDEN"),
+				Managedby:             types.StringValue("other-tool"),
+			},
+			want: map[string]any{
+				"comments":                 "new",
+				"sync":                     "DISABLED",
+				"copies":                   int64(3),
+				"quota":                    int64(20 << 30),
+				"special_small_block_size": int64(128 << 10),
+				"xattr":                    "ON",
+				"snapdev":                  "HIDDEN",
+				"managedby":                "other-tool",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := datasetUpdateParams(&tt.plan, &state)
+			for key, want := range tt.want {
+				got, ok := params[key]
+				if !ok {
+					t.Errorf("%s: missing", key)
+				} else if got != want {
+					t.Errorf("%s = %#v, want %#v", key, got, want)
+				}
+			}
+			for _, key := range tt.omitted {
+				if got, ok := params[key]; ok {
+					t.Errorf("%s = %#v, want it omitted", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPopulateDatasetStateShareType(t *testing.T) {
+	result := poolDatasetResult{
+		ID:              "tank/smb",
+		Name:            "tank/smb",
+		Acltype:         zfsProperty{Value: "NFSV4", Source: "LOCAL"},
+		Aclmode:         zfsProperty{Value: "RESTRICTED", Source: "LOCAL"},
+		Casesensitivity: zfsProperty{Value: "INSENSITIVE", Source: "LOCAL"},
+	}
+
+	tests := []struct {
+		name      string
+		shareType types.String
+		want      types.String
+	}{
+		{name: "preset", shareType: types.StringValue("SMB"), want: types.StringNull()},
+		{name: "generic", shareType: types.StringValue("GENERIC"), want: types.StringValue("NFSV4")},
+		{name: "imported", shareType: types.StringNull(), want: types.StringValue("NFSV4")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := poolDatasetResourceModel{Name: types.StringValue("tank/smb"), ShareType: tt.shareType}
+			populateDatasetState(&model, &result, "")
+			if !model.Acltype.Equal(tt.want) {
+				t.Errorf("acltype = %v, want %v", model.Acltype, tt.want)
+			}
+		})
+	}
+}
+
 func testAccPoolDatasetResourceConfig(name string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test" {
-  name = %q
+  name                = %q
+  deletion_protection = false
 }
 `, name)
 }
@@ -119,12 +551,13 @@ resource "truenas_pool_dataset" "test" {
 func testAccPoolDatasetResourceConfigFull(name string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test" {
-  name        = %q
-  compression = "LZ4"
-  atime       = "OFF"
-  sync        = "STANDARD"
-  copies      = 2
-  comments    = "managed by terraform"
+  name                = %q
+  deletion_protection = false
+  compression         = "LZ4"
+  atime               = "OFF"
+  sync                = "STANDARD"
+  copies              = 2
+  comments            = "managed by terraform"
 }
 `, name)
 }
@@ -132,8 +565,9 @@ resource "truenas_pool_dataset" "test" {
 func testAccPoolDatasetResourceConfigWithCompression(name, compression string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test" {
-  name        = %q
-  compression = %q
+  name                = %q
+  deletion_protection = false
+  compression         = %q
 }
 `, name, compression)
 }
@@ -141,8 +575,181 @@ resource "truenas_pool_dataset" "test" {
 func testAccPoolDatasetResourceConfigNested(name string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test" {
-  name             = %q
-  create_ancestors = true
+  name                = %q
+  deletion_protection = false
+  create_ancestors    = true
+}
+`, name)
+}
+
+func testAccPoolDatasetResourceConfigWithQuota(name, quota string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+  quota               = %q
+}
+`, name, quota)
+}
+
+func testAccPoolDatasetResourceConfigProtected(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name = %q
+}
+`, name)
+}
+
+func testAccPoolDatasetResourceConfigOwned(name, tag string) string {
+	return fmt.Sprintf(`
+provider "truenas" {
+  host          = %q
+  api_key       = %q
+  insecure      = true
+  ownership_tag = %q
+}
+
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+}
+`, os.Getenv("TRUENAS_HOST"), os.Getenv("TRUENAS_API_KEY"), tag, name)
+}
+
+func testAccPoolDatasetResourceConfigRelative(pool, name string) string {
+	return fmt.Sprintf(`
+provider "truenas" {
+  host         = %q
+  api_key      = %q
+  insecure     = true
+  default_pool = %q
+}
+
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+}
+`, os.Getenv("TRUENAS_HOST"), os.Getenv("TRUENAS_API_KEY"), pool, name)
+}
+
+func testAccPoolDatasetResourceConfigClone(golden, clone string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "golden" {
+  name                = %q
+  deletion_protection = false
+}
+
+resource "truenas_pool_snapshot" "base" {
+  dataset       = truenas_pool_dataset.golden.id
+  name          = "base"
+  defer_destroy = true
+}
+
+resource "truenas_pool_dataset" "clone" {
+  name                = %q
+  clone_from_snapshot = truenas_pool_snapshot.base.id
+  compression         = "ZSTD"
+  deletion_protection = false
+}
+`, golden, clone)
+}
+
+func testAccPoolDatasetResourceConfigEncrypted(name string, locked bool) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                     = %q
+  encryption               = true
+  encryption_algorithm     = "AES-256-GCM"
+  encryption_passphrase_wo = "tf-acc-test-passphrase"
+  locked                   = %t
+  deletion_protection      = false
+}
+`, name, locked)
+}
+
+func testAccPoolDatasetResourceConfigRename(parent, name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "parent" {
+  name                = %q
+  deletion_protection = false
+}
+
+resource "truenas_pool_dataset" "test" {
+  name                = "${truenas_pool_dataset.parent.name}/%s"
+  deletion_protection = false
+}
+
+resource "truenas_nfs_share" "test" {
+  path = truenas_pool_dataset.test.mountpoint
+}
+`, parent, name)
+}
+
+func testAccPoolDatasetResourceConfigProperties(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+  share_type          = "SMB"
+  xattr               = "SA"
+  snapdev             = "VISIBLE"
+  managedby           = "backup-tool"
+}
+
+resource "truenas_pool_dataset" "child" {
+  name                = "${truenas_pool_dataset.test.name}/child"
+  deletion_protection = false
+  sync                = "ALWAYS"
+}
+`, name)
+}
+
+func testAccPoolDatasetResourceConfigWithShareType(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+  share_type          = "SMB"
+}
+`, name)
+}
+
+func testAccPoolDatasetResourceConfigWithShareTypeAcl(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+  share_type          = "SMB"
+  acltype             = "POSIX"
+}
+`, name)
+}
+
+// The child inherits the user properties of the parent, which it must not report.
+func testAccPoolDatasetResourceConfigUserProperties(name, props string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+  user_properties     = %s
+}
+
+resource "truenas_pool_dataset" "child" {
+  name                = "${truenas_pool_dataset.test.name}/child"
+  deletion_protection = false
+}
+`, name, props)
+}
+
+func testAccPoolDatasetResourceConfigRotated(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                    = %q
+  encryption              = true
+  encryption_algorithm    = "AES-256-GCM"
+  encryption_generate_key = true
+  encryption_wo_version   = 2
+  deletion_protection     = false
 }
 `, name)
 }
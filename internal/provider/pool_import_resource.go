@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+var (
+	_ resource.Resource                     = (*poolImportResource)(nil)
+	_ resource.ResourceWithConfigure        = (*poolImportResource)(nil)
+	_ resource.ResourceWithImportState      = (*poolImportResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*poolImportResource)(nil)
+)
+
+type poolImportResource struct {
+	client *client.Client
+}
+
+type poolImportResourceModel struct {
+	ID              types.Int64    `tfsdk:"id"`
+	GUID            types.String   `tfsdk:"guid"`
+	Name            types.String   `tfsdk:"name"`
+	Cascade         types.Bool     `tfsdk:"cascade"`
+	RestartServices types.Bool     `tfsdk:"restart_services"`
+	Path            types.String   `tfsdk:"path"`
+	Status          types.String   `tfsdk:"status"`
+	Healthy         types.Bool     `tfsdk:"healthy"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+type poolImportResult struct {
+	ID      int64  `json:"id"`
+	GUID    string `json:"guid"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Healthy bool   `json:"healthy"`
+}
+
+// importablePool is an entry of the pool.import_find result.
+type importablePool struct {
+	Name     string `json:"name"`
+	GUID     string `json:"guid"`
+	Status   string `json:"status"`
+	Hostname string `json:"hostname"`
+}
+
+func NewPoolImportResource() resource.Resource {
+	return &poolImportResource{}
+}
+
+func (r *poolImportResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pool_import"
+}
+
+func (r *poolImportResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Imports an existing ZFS pool, e.g. after moving its disks to another system, and exports it when destroyed. " +
+			"The data on the pool is never destroyed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "The unique identifier of the pool once imported.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"guid": schema.StringAttribute{
+				Description: "The GUID of the pool to import. Exactly one of guid or name must be set. Changing it imports another pool.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the pool to import. Exactly one of guid or name must be set; the name must not be shared by several importable pools. Changing it imports another pool.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cascade": schema.BoolAttribute{
+				Description: "When exporting the pool, also delete the shares, tasks and other configuration that refer to it. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"restart_services": schema.BoolAttribute{
+				Description: "When exporting the pool, restart the services that use it instead of failing while they are running. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"path": schema.StringAttribute{
+				Description: "The mount path of the pool (e.g. /mnt/tank).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "The pool status (e.g. ONLINE, DEGRADED).",
+				Computed:    true,
+			},
+			"healthy": schema.BoolAttribute{
+				Description: "Whether the pool is healthy.",
+				Computed:    true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Delete: true}),
+		},
+	}
+}
+
+func (r *poolImportResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("guid"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (r *poolImportResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+}
+
+func (r *poolImportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan poolImportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	var candidates []importablePool
+	if err := r.client.CallJob(ctx, "pool.import_find", []any{}, &candidates); err != nil {
+		resp.Diagnostics.AddError("Error Finding Importable Pools", err.Error())
+		return
+	}
+
+	found, err := findImportablePool(candidates, plan.GUID.ValueString(), plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Importing Pool", err.Error())
+		return
+	}
+
+	err = r.client.CallJob(ctx, "pool.import_pool", []any{map[string]any{"guid": found.GUID}}, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Importing Pool", err.Error())
+		return
+	}
+
+	var pools []poolImportResult
+	err = r.client.Call(ctx, "pool.query", []any{[]any{[]any{"guid", "=", found.GUID}}}, &pools)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Pool After Import", err.Error())
+		return
+	}
+	if len(pools) == 0 {
+		resp.Diagnostics.AddError("Error Reading Pool After Import", fmt.Sprintf("Pool %q (%s) was not found after the import.", found.Name, found.GUID))
+		return
+	}
+
+	populatePoolImportState(&plan, &pools[0])
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolImportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state poolImportResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var result poolImportResult
+	err := r.client.Call(ctx, "pool.get_instance", []any{state.ID.ValueInt64()}, &result)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error Reading Pool", err.Error())
+		return
+	}
+
+	populatePoolImportState(&state, &result)
+	if state.Cascade.IsNull() {
+		state.Cascade = types.BoolValue(false)
+	}
+	if state.RestartServices.IsNull() {
+		state.RestartServices = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update only changes the export options, which are used on delete.
+func (r *poolImportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state poolImportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Status = state.Status
+	plan.Healthy = state.Healthy
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolImportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state poolImportResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	exportPool(ctx, r.client, state.ID.ValueInt64(), map[string]any{
+		"cascade":          state.Cascade.ValueBool(),
+		"restart_services": state.RestartServices.ValueBool(),
+		"destroy":          false,
+	}, &resp.Diagnostics)
+}
+
+func (r *poolImportResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := resolveImportID(ctx, r.client, req.ID, lookupPool)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Importing Pool",
+			fmt.Sprintf("Could not resolve import ID %q: %s", req.ID, err.Error()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+func populatePoolImportState(model *poolImportResourceModel, result *poolImportResult) {
+	model.ID = types.Int64Value(result.ID)
+	model.GUID = types.StringValue(result.GUID)
+	model.Name = types.StringValue(result.Name)
+	model.Path = types.StringValue(result.Path)
+	model.Status = types.StringValue(result.Status)
+	model.Healthy = types.BoolValue(result.Healthy)
+}
+
+// findImportablePool returns the pool of candidates with the given GUID, or else the
+// only one with the given name.
+func findImportablePool(candidates []importablePool, guid, name string) (importablePool, error) {
+	var matches []importablePool
+	for _, p := range candidates {
+		if (guid != "" && p.GUID == guid) || (guid == "" && p.Name == name) {
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		if guid != "" {
+			return importablePool{}, fmt.Errorf("no importable pool has the GUID %s; it may already be imported or its disks may be missing", guid)
+		}
+		return importablePool{}, fmt.Errorf("no importable pool is named %q; it may already be imported or its disks may be missing", name)
+	default:
+		guids := make([]string, len(matches))
+		for i, p := range matches {
+			guids[i] = p.GUID
+		}
+		return importablePool{}, fmt.Errorf("%d importable pools are named %q (GUIDs %s); set guid instead", len(matches), name, strings.Join(guids, ", "))
+	}
+}
+
+// exportPool runs pool.export for the pool with the given ID. A pool that no longer
+// exists is not an error.
+func exportPool(ctx context.Context, c *client.Client, id int64, options map[string]any, diags *diag.Diagnostics) {
+	err := c.CallJob(ctx, "pool.export", []any{id, options}, nil)
+	if err != nil && !isNotFound(err) {
+		diags.AddError("Error Exporting Pool", err.Error())
+	}
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestFindImportablePool(t *testing.T) {
+	candidates := []importablePool{
+		{Name: "tank", GUID: "111"},
+		{Name: "backup", GUID: "222"},
+		{Name: "backup", GUID: "333"},
+	}
+
+	tests := []struct {
+		name     string
+		guid     string
+		pool     string
+		wantGUID string
+		wantErr  string
+	}{
+		{name: "by name", pool: "tank", wantGUID: "111"},
+		{name: "by guid", guid: "333", wantGUID: "333"},
+		{name: "ambiguous name", pool: "backup", wantErr: "GUIDs 222, 333"},
+		{name: "unknown name", pool: "vault", wantErr: `named "vault"`},
+		{name: "unknown guid", guid: "999", wantErr: "GUID 999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findImportablePool(candidates, tt.guid, tt.pool)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.GUID != tt.wantGUID {
+				t.Errorf("got GUID %s, want %s", got.GUID, tt.wantGUID)
+			}
+		})
+	}
+}
+
+func TestAccPoolImportResource_basic(t *testing.T) {
+	name := os.Getenv("TRUENAS_TEST_EXPORTED_POOL")
+	if name == "" {
+		t.Skip("TRUENAS_TEST_EXPORTED_POOL must name an exported pool that can be imported")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolImportResourceConfig(name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_import.test", "name", name),
+					resource.TestCheckResourceAttr("truenas_pool_import.test", "path", "/mnt/"+name),
+					resource.TestCheckResourceAttrSet("truenas_pool_import.test", "id"),
+					resource.TestCheckResourceAttrSet("truenas_pool_import.test", "guid"),
+				),
+			},
+			{
+				ResourceName:      "truenas_pool_import.test",
+				ImportState:       true,
+				ImportStateId:     name,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccPoolImportResourceConfig(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_import" "test" {
+  name = %q
+}
+`, name)
+}
@@ -0,0 +1,571 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+var (
+	_ resource.Resource                = (*poolResource)(nil)
+	_ resource.ResourceWithConfigure   = (*poolResource)(nil)
+	_ resource.ResourceWithImportState = (*poolResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*poolResource)(nil)
+)
+
+type poolResource struct {
+	client *client.Client
+}
+
+type poolResourceModel struct {
+	ID                   types.Int64    `tfsdk:"id"`
+	Name                 types.String   `tfsdk:"name"`
+	Topology             types.Object   `tfsdk:"topology"`
+	Encryption           types.Bool     `tfsdk:"encryption"`
+	EncryptionAlgorithm  types.String   `tfsdk:"encryption_algorithm"`
+	EncryptionPassphrase types.String   `tfsdk:"encryption_passphrase_wo"`
+	EncryptionWOVersion  types.Int64    `tfsdk:"encryption_wo_version"`
+	Checksum             types.String   `tfsdk:"checksum"`
+	Deduplication        types.String   `tfsdk:"deduplication"`
+	DestroyOnDelete      types.Bool     `tfsdk:"destroy_on_delete"`
+	DeletionProtection   types.Bool     `tfsdk:"deletion_protection"`
+	Path                 types.String   `tfsdk:"path"`
+	Status               types.String   `tfsdk:"status"`
+	Healthy              types.Bool     `tfsdk:"healthy"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
+}
+
+type poolResourceResult struct {
+	ID       int64              `json:"id"`
+	Name     string             `json:"name"`
+	Path     string             `json:"path"`
+	Status   string             `json:"status"`
+	Healthy  bool               `json:"healthy"`
+	Topology poolTopologyResult `json:"topology"`
+}
+
+// poolRootDatasetResult holds the properties of a pool's root dataset that are set
+// through truenas_pool.
+type poolRootDatasetResult struct {
+	Encrypted           bool        `json:"encrypted"`
+	EncryptionAlgorithm zfsProperty `json:"encryption_algorithm"`
+	Checksum            zfsProperty `json:"checksum"`
+	Deduplication       zfsProperty `json:"deduplication"`
+}
+
+var poolVdevTypes = []string{"STRIPE", "MIRROR", "RAIDZ1", "RAIDZ2", "RAIDZ3"}
+
+func NewPoolResource() resource.Resource {
+	return &poolResource{}
+}
+
+func (r *poolResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pool"
+}
+
+func poolVdevListAttribute(description string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: description,
+		Optional:    true,
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+			singleStripeVdevValidator{},
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"type": schema.StringAttribute{
+					Description: "Vdev type: STRIPE, MIRROR, RAIDZ1, RAIDZ2 or RAIDZ3. A STRIPE vdev adds each of its disks as a separate vdev; a category has at most one.",
+					Required:    true,
+					Validators: []validator.String{
+						stringvalidator.OneOf(poolVdevTypes...),
+					},
+				},
+				"disks": schema.ListAttribute{
+					Description: "Serial numbers or TrueNAS identifiers (e.g. {serial_lunid}5000c500a1b2c3d4) of the disks in the vdev.",
+					Required:    true,
+					ElementType: types.StringType,
+					Validators: []validator.List{
+						listvalidator.SizeAtLeast(1),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *poolResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a TrueNAS ZFS pool. Vdevs and spares can be added in place; any other topology change is rejected at plan time. " +
+			"Destroying the resource exports the pool, keeping its data on the disks, unless destroy_on_delete is set.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "The unique identifier of the pool.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the pool. Cannot be changed after creation.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"topology": schema.SingleNestedAttribute{
+				Description: "The vdevs of the pool. Vdevs can be appended to a category, disks appended to a STRIPE vdev, and spares added; existing vdevs cannot be changed or removed.",
+				Required:    true,
+				Attributes: map[string]schema.Attribute{
+					"data": func() schema.ListNestedAttribute {
+						a := poolVdevListAttribute("Data vdevs.")
+						a.Optional = false
+						a.Required = true
+						return a
+					}(),
+					"log":     poolVdevListAttribute("Log (SLOG) vdevs, STRIPE or MIRROR."),
+					"cache":   poolVdevListAttribute("Cache (L2ARC) vdevs, STRIPE only."),
+					"special": poolVdevListAttribute("Special allocation class vdevs, for metadata and small blocks."),
+					"dedup":   poolVdevListAttribute("Deduplication table vdevs."),
+					"spares": schema.ListAttribute{
+						Description: "Serial numbers or TrueNAS identifiers of hot spare disks.",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
+					},
+				},
+			},
+			"encryption": schema.BoolAttribute{
+				Description: "Whether the root dataset of the pool is encrypted. Cannot be changed after creation. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"encryption_algorithm": schema.StringAttribute{
+				Description: "Encryption algorithm, e.g. AES-256-GCM. Defaults to the server's default when encryption is enabled. Cannot be changed after creation.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"encryption_passphrase_wo": schema.StringAttribute{
+				Description: "Passphrase protecting the encryption key of the root dataset (at least 8 characters). Without one, TrueNAS generates a key and stores it in its database. " +
+					"Write-only: used during creation and when encryption_wo_version changes, and never stored in state. Requires Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(8),
+				},
+			},
+			"encryption_wo_version": schema.Int64Attribute{
+				Description: "Change this value to apply the current encryption_passphrase_wo to the root dataset with pool.dataset.change_key, " +
+					"e.g. to rotate the passphrase or switch between a passphrase and a generated key. The pool is changed in place and its data is not re-encrypted.",
+				Optional: true,
+			},
+			"checksum": schema.StringAttribute{
+				Description: "Checksum algorithm of the root dataset, inherited by the other datasets. Must be one of the values offered by the server. Null means the ZFS default.",
+				Optional:    true,
+			},
+			"deduplication": schema.StringAttribute{
+				Description: "Deduplication of the root dataset, inherited by the other datasets: ON, VERIFY or OFF. Null means the ZFS default.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "VERIFY", "OFF"),
+				},
+			},
+			"destroy_on_delete": schema.BoolAttribute{
+				Description: "Destroy the pool and its data, instead of exporting it, when the resource is destroyed. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"deletion_protection": deletionProtectionAttribute("pool"),
+			"path": schema.StringAttribute{
+				Description: "The mount path of the pool (e.g. /mnt/tank).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "The pool status (e.g. ONLINE, DEGRADED).",
+				Computed:    true,
+			},
+			"healthy": schema.BoolAttribute{
+				Description: "Whether the pool is healthy.",
+				Computed:    true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
+		},
+	}
+}
+
+func (r *poolResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+}
+
+// ModifyPlan validates the checksum against the server and rejects topology changes
+// other than additions.
+func (r *poolResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state poolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateServerChoice(ctx, r.client, "pool.dataset.checksum_choices", []any{}, path.Root("checksum"), plan.Checksum, state.Checksum, &resp.Diagnostics)
+
+	// Write-only values are null in the plan, so the passphrase is read from the configuration.
+	var passphrase types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("encryption_passphrase_wo"), &passphrase)...)
+	if !plan.Encryption.IsUnknown() && !plan.Encryption.ValueBool() {
+		if !passphrase.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("encryption_passphrase_wo"), "Encryption Not Enabled",
+				"encryption_passphrase_wo can only be set when encryption is true.")
+		}
+		if !plan.EncryptionWOVersion.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("encryption_wo_version"), "Encryption Not Enabled",
+				"encryption_wo_version can only be set when encryption is true.")
+		}
+	}
+
+	if req.State.Raw.IsNull() || plan.Topology.IsUnknown() {
+		return
+	}
+	current := poolTopologyFromObject(ctx, state.Topology, &resp.Diagnostics)
+	planned := poolTopologyFromObject(ctx, plan.Topology, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if _, err := topologyAdditions(current, planned); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("topology"), "Unsupported Topology Change",
+			fmt.Sprintf("The planned topology cannot be applied to pool %q in place: %s.", state.Name.ValueString(), err.Error()))
+	}
+}
+
+func (r *poolResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan poolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	topology := poolTopologyFromObject(ctx, plan.Topology, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	topologyParam := r.topologyParams(ctx, topology, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config poolResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// pool.create has no parent to inherit from, so unset values are omitted rather
+	// than sent as INHERIT.
+	params := map[string]any{
+		"name":       plan.Name.ValueString(),
+		"topology":   topologyParam,
+		"encryption": plan.Encryption.ValueBool(),
+	}
+	if plan.Encryption.ValueBool() {
+		options := map[string]any{
+			"generate_key": config.EncryptionPassphrase.IsNull(),
+		}
+		if !config.EncryptionPassphrase.IsNull() {
+			options["passphrase"] = config.EncryptionPassphrase.ValueString()
+		}
+		if !plan.EncryptionAlgorithm.IsNull() && !plan.EncryptionAlgorithm.IsUnknown() {
+			options["algorithm"] = plan.EncryptionAlgorithm.ValueString()
+		}
+		params["encryption_options"] = options
+	}
+	if !plan.Checksum.IsNull() {
+		params["checksum"] = plan.Checksum.ValueString()
+	}
+	if !plan.Deduplication.IsNull() {
+		params["deduplication"] = plan.Deduplication.ValueString()
+	}
+
+	var result poolResourceResult
+	err := r.client.CallJob(ctx, "pool.create", []any{params}, &result)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Pool", err.Error())
+		return
+	}
+
+	plan.ID = types.Int64Value(result.ID)
+	if !r.read(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.AddError("Error Reading Pool After Creation", fmt.Sprintf("Pool %q was not found after creation.", plan.Name.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state poolResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.read(ctx, &state, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	state.DeletionProtection = deletionProtectionValue(state.DeletionProtection)
+	if state.DestroyOnDelete.IsNull() {
+		state.DestroyOnDelete = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *poolResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state poolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	current := poolTopologyFromObject(ctx, state.Topology, &resp.Diagnostics)
+	planned := poolTopologyFromObject(ctx, plan.Topology, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	added, err := topologyAdditions(current, planned)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("topology"), "Unsupported Topology Change", err.Error())
+		return
+	}
+
+	if !added.isEmpty() {
+		topologyParam := r.topologyParams(ctx, added, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		err := r.client.CallJob(ctx, "pool.update", []any{state.ID.ValueInt64(), map[string]any{"topology": topologyParam}}, nil)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Adding Vdevs to Pool", err.Error())
+			return
+		}
+	}
+
+	if plan.Encryption.ValueBool() && !plan.EncryptionWOVersion.Equal(state.EncryptionWOVersion) {
+		var config poolResourceModel
+		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		options := map[string]any{
+			"generate_key": config.EncryptionPassphrase.IsNull(),
+			"key_file":     false,
+		}
+		if !config.EncryptionPassphrase.IsNull() {
+			options["passphrase"] = config.EncryptionPassphrase.ValueString()
+		}
+		err := r.client.CallJob(ctx, "pool.dataset.change_key", []any{state.Name.ValueString(), options}, nil)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Changing Pool Encryption Key", err.Error())
+			return
+		}
+	}
+
+	if !plan.Checksum.Equal(state.Checksum) || !plan.Deduplication.Equal(state.Deduplication) {
+		params := map[string]any{}
+		setStringParamOrInherit(params, "checksum", plan.Checksum)
+		setStringParamOrInherit(params, "deduplication", plan.Deduplication)
+		err := r.client.Call(ctx, "pool.dataset.update", []any{state.Name.ValueString(), params}, nil)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Updating Pool Root Dataset", err.Error())
+			return
+		}
+	}
+
+	if !r.read(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.AddError("Error Reading Pool After Update", fmt.Sprintf("Pool %q was not found after the update.", plan.Name.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state poolResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !checkDeletionProtection("pool", state.Name.ValueString(), state.DeletionProtection, &resp.Diagnostics) {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// Exporting keeps the data on the disks, so the pool can be imported again.
+	exportPool(ctx, r.client, state.ID.ValueInt64(), map[string]any{
+		"cascade":          false,
+		"restart_services": false,
+		"destroy":          state.DestroyOnDelete.ValueBool(),
+	}, &resp.Diagnostics)
+}
+
+func (r *poolResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := resolveImportID(ctx, r.client, req.ID, lookupPool)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Importing Pool",
+			fmt.Sprintf("Could not resolve import ID %q: %s", req.ID, err.Error()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// read refreshes model from the pool with model's ID and its root dataset. It returns
+// false if the pool does not exist or could not be read; errors are added to diags.
+func (r *poolResource) read(ctx context.Context, model *poolResourceModel, diags *diag.Diagnostics) bool {
+	var result poolResourceResult
+	err := r.client.Call(ctx, "pool.get_instance", []any{model.ID.ValueInt64()}, &result)
+	if err != nil {
+		if !isNotFound(err) {
+			diags.AddError("Error Reading Pool", err.Error())
+		}
+		return false
+	}
+
+	var disks []diskResult
+	if err := r.client.Call(ctx, "disk.query", []any{}, &disks); err != nil {
+		diags.AddError("Error Reading Disks", err.Error())
+		return false
+	}
+
+	var root poolRootDatasetResult
+	if err := r.client.Call(ctx, "pool.dataset.get_instance", []any{result.Name}, &root); err != nil {
+		diags.AddError("Error Reading Pool Root Dataset", err.Error())
+		return false
+	}
+
+	var prior poolTopology
+	if !model.Topology.IsNull() && !model.Topology.IsUnknown() {
+		prior = poolTopologyFromObject(ctx, model.Topology, diags)
+	}
+	topology, d := types.ObjectValueFrom(ctx, poolTopologyAttrTypes, topologyFromResult(result.Topology, disks, prior))
+	diags.Append(d...)
+
+	model.ID = types.Int64Value(result.ID)
+	model.Name = types.StringValue(result.Name)
+	model.Topology = topology
+	model.Path = types.StringValue(result.Path)
+	model.Status = types.StringValue(result.Status)
+	model.Healthy = types.BoolValue(result.Healthy)
+	model.Encryption = types.BoolValue(root.Encrypted)
+	if root.Encrypted {
+		model.EncryptionAlgorithm = types.StringValue(root.EncryptionAlgorithm.stringValue())
+	} else {
+		model.EncryptionAlgorithm = types.StringNull()
+	}
+	model.Checksum = readStringProperty(&root.Checksum)
+	model.Deduplication = readStringProperty(&root.Deduplication)
+
+	return !diags.HasError()
+}
+
+// topologyParams resolves the disks of t to device names for pool.create or pool.update.
+func (r *poolResource) topologyParams(ctx context.Context, t poolTopology, diags *diag.Diagnostics) map[string]any {
+	var disks []diskResult
+	if err := r.client.Call(ctx, "disk.query", []any{}, &disks); err != nil {
+		diags.AddError("Error Reading Disks", err.Error())
+		return nil
+	}
+
+	params, err := topologyParams(t, poolDiskNames(disks))
+	if err != nil {
+		diags.AddAttributeError(path.Root("topology"), "Invalid Pool Topology", err.Error())
+		return nil
+	}
+	return params
+}
+
+func poolTopologyFromObject(ctx context.Context, obj types.Object, diags *diag.Diagnostics) poolTopology {
+	var t poolTopology
+	diags.Append(obj.As(ctx, &t, basetypes.ObjectAsOptions{})...)
+	return t
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccPoolDisks returns the serial numbers of spare disks that the pool tests may
+// wipe, from the comma-separated TRUENAS_TEST_POOL_DISKS.
+func testAccPoolDisks(t *testing.T, n int) []string {
+	var disks []string
+	for _, d := range strings.Split(os.Getenv("TRUENAS_TEST_POOL_DISKS"), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			disks = append(disks, d)
+		}
+	}
+	if len(disks) < n {
+		t.Skipf("TRUENAS_TEST_POOL_DISKS must list at least %d disks that can be wiped", n)
+	}
+	return disks[:n]
+}
+
+func TestAccPoolResource_basic(t *testing.T) {
+	disks := testAccPoolDisks(t, 3)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolResourceConfig(fmt.Sprintf(`[{ type = "MIRROR", disks = [%q, %q] }]`, disks[0], disks[1]), ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool.test", "name", "tfacctest"),
+					resource.TestCheckResourceAttr("truenas_pool.test", "path", "/mnt/tfacctest"),
+					resource.TestCheckResourceAttr("truenas_pool.test", "topology.data.0.type", "MIRROR"),
+					resource.TestCheckResourceAttr("truenas_pool.test", "topology.data.0.disks.0", disks[0]),
+					resource.TestCheckResourceAttr("truenas_pool.test", "encryption", "false"),
+					resource.TestCheckResourceAttr("truenas_pool.test", "healthy", "true"),
+				),
+			},
+			{
+				Config: testAccPoolResourceConfig(fmt.Sprintf(`[{ type = "MIRROR", disks = [%q, %q] }]`, disks[0], disks[1]), fmt.Sprintf(`spares = [%q]`, disks[2])),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool.test", "topology.spares.#", "1"),
+					resource.TestCheckResourceAttr("truenas_pool.test", "topology.spares.0", disks[2]),
+				),
+			},
+			{
+				ResourceName:            "truenas_pool.test",
+				ImportState:             true,
+				ImportStateId:           "tfacctest",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "destroy_on_delete"},
+			},
+		},
+	})
+}
+
+func testAccPoolResourceConfig(data, spares string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool" "test" {
+  name = "tfacctest"
+
+  topology = {
+    data = %s
+    %s
+  }
+
+  destroy_on_delete   = true
+  deletion_protection = false
+}
+`, data, spares)
+}
@@ -0,0 +1,428 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+var (
+	_ resource.Resource                = (*poolSnapshotResource)(nil)
+	_ resource.ResourceWithConfigure   = (*poolSnapshotResource)(nil)
+	_ resource.ResourceWithImportState = (*poolSnapshotResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*poolSnapshotResource)(nil)
+)
+
+// snapshotHoldTag is the tag of the ZFS hold placed by the hold attribute, which is
+// also the tag used by the TrueNAS UI.
+const snapshotHoldTag = "truenas"
+
+type poolSnapshotResource struct {
+	client   *client.Client
+	provider *providerData
+}
+
+type poolSnapshotResourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Dataset         types.String   `tfsdk:"dataset"`
+	ResolvedDataset types.String   `tfsdk:"resolved_dataset"`
+	Name            types.String   `tfsdk:"name"`
+	Recursive       types.Bool     `tfsdk:"recursive"`
+	Exclude         types.List     `tfsdk:"exclude"`
+	Properties      types.Map      `tfsdk:"properties"`
+	Hold            types.Bool     `tfsdk:"hold"`
+	DeferDestroy    types.Bool     `tfsdk:"defer_destroy"`
+	Referenced      types.Int64    `tfsdk:"referenced"`
+	Used            types.Int64    `tfsdk:"used"`
+	Createtxg       types.String   `tfsdk:"createtxg"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+type poolSnapshotProperties struct {
+	Referenced zfsProperty `json:"referenced"`
+	Used       zfsProperty `json:"used"`
+	Createtxg  zfsProperty `json:"createtxg"`
+}
+
+type poolSnapshotResult struct {
+	ID           string                 `json:"id"`
+	Dataset      string                 `json:"dataset"`
+	SnapshotName string                 `json:"snapshot_name"`
+	Properties   poolSnapshotProperties `json:"properties"`
+	Holds        map[string]any         `json:"holds"`
+}
+
+func NewPoolSnapshotResource() resource.Resource {
+	return &poolSnapshotResource{}
+}
+
+func (r *poolSnapshotResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pool_snapshot"
+}
+
+func (r *poolSnapshotResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a named ZFS snapshot, e.g. one taken before a migration. For periodic snapshots, use truenas_pool_snapshot_task.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The full snapshot name, dataset@name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"dataset": schema.StringAttribute{
+				Description: "The dataset or zvol to snapshot, e.g. \"tank/data\", or a path relative to the provider's default_pool, e.g. \"./data\".",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"resolved_dataset": resolvedPathAttribute("dataset", "tank/data"),
+			"name": schema.StringAttribute{
+				Description: "The snapshot name, the part after @.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^@/]+$`), "must not be empty or contain @ or /"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"recursive": schema.BoolAttribute{
+				Description: "Also snapshot the child datasets, with the same name. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"exclude": schema.ListAttribute{
+				Description: "Child datasets to leave out of a recursive snapshot.",
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"properties": schema.MapAttribute{
+				Description: "ZFS user properties to set on the snapshot, e.g. {\"org.example:reason\" = \"pre-migration\"}. Only used during creation.",
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"hold": schema.BoolAttribute{
+				Description: "Place a ZFS hold on the snapshot (and on the snapshots of child datasets when recursive), so that it cannot be destroyed outside Terraform. " +
+					"The hold is released before the resource destroys the snapshot. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"defer_destroy": schema.BoolAttribute{
+				Description: "When destroying the resource, mark the snapshot for deferred destruction instead of failing if it has clones; " +
+					"ZFS destroys it once the clones are gone. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"referenced": schema.Int64Attribute{
+				Description: "The amount of data, in bytes, accessible through the snapshot.",
+				Computed:    true,
+			},
+			"used": schema.Int64Attribute{
+				Description: "The space, in bytes, that destroying the snapshot would free.",
+				Computed:    true,
+			},
+			"createtxg": schema.StringAttribute{
+				Description: "The ZFS transaction group in which the snapshot was taken.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
+		},
+	}
+}
+
+func (r *poolSnapshotResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.provider = data
+}
+
+// ModifyPlan resolves relative dataset paths and checks that exclude is only used for
+// recursive snapshots.
+func (r *poolSnapshotResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state poolSnapshotResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkRelativePath(r.provider, path.Root("dataset"), plan.Dataset, &resp.Diagnostics)
+	ignoreEquivalentReplace(r.provider, datasetNamePath, path.Root("dataset"), plan.Dataset, state.Dataset, resp)
+	planResolvedPath(ctx, r.provider, datasetNamePath, path.Root("resolved_dataset"), plan.Dataset, resp)
+
+	if !plan.Exclude.IsNull() && !plan.Recursive.IsUnknown() && !plan.Recursive.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("exclude"), "Exclude Requires Recursive",
+			"exclude can only be set when recursive is true.")
+	}
+}
+
+func (r *poolSnapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan poolSnapshotResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	params := map[string]any{
+		"dataset":   resolvePoolPath(r.provider.defaultPool, datasetNamePath, plan.Dataset.ValueString()),
+		"name":      plan.Name.ValueString(),
+		"recursive": plan.Recursive.ValueBool(),
+	}
+	if !plan.Exclude.IsNull() {
+		var exclude []string
+		resp.Diagnostics.Append(plan.Exclude.ElementsAs(ctx, &exclude, false)...)
+		params["exclude"] = exclude
+	}
+	if !plan.Properties.IsNull() {
+		var properties map[string]string
+		resp.Diagnostics.Append(plan.Properties.ElementsAs(ctx, &properties, false)...)
+		params["properties"] = properties
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var result poolSnapshotResult
+	err := r.client.Call(ctx, "pool.snapshot.create", []any{params}, &result)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Snapshot", err.Error())
+		return
+	}
+	plan.ID = types.StringValue(result.ID)
+
+	if plan.Hold.ValueBool() {
+		if err := r.setHold(ctx, result.ID, true, plan.Recursive.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Error Holding Snapshot", err.Error())
+			// The snapshot exists; keep it in state so that it is not orphaned.
+			plan.Hold = types.BoolValue(false)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			return
+		}
+	}
+
+	if !r.read(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.AddError("Error Reading Snapshot After Creation", fmt.Sprintf("Snapshot %q was not found after creation.", result.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolSnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state poolSnapshotResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.read(ctx, &state, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	if state.Recursive.IsNull() {
+		state.Recursive = types.BoolValue(false)
+	}
+	if state.DeferDestroy.IsNull() {
+		state.DeferDestroy = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update places or releases the hold; defer_destroy is only used on delete.
+func (r *poolSnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state poolSnapshotResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if !plan.Hold.Equal(state.Hold) {
+		if err := r.setHold(ctx, state.ID.ValueString(), plan.Hold.ValueBool(), state.Recursive.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Error Updating Snapshot Hold", err.Error())
+			return
+		}
+	}
+
+	if !r.read(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.AddError("Error Reading Snapshot After Update", fmt.Sprintf("Snapshot %q was not found after the update.", state.ID.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolSnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state poolSnapshotResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if state.Hold.ValueBool() {
+		if err := r.setHold(ctx, state.ID.ValueString(), false, state.Recursive.ValueBool()); err != nil {
+			if isNotFound(err) {
+				return
+			}
+			resp.Diagnostics.AddError("Error Releasing Snapshot Hold", err.Error())
+			return
+		}
+	}
+
+	deleteOpts := map[string]any{
+		"defer":     state.DeferDestroy.ValueBool(),
+		"recursive": state.Recursive.ValueBool(),
+	}
+
+	err := r.client.Call(ctx, "pool.snapshot.delete", []any{state.ID.ValueString(), deleteOpts}, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError("Error Deleting Snapshot", err.Error())
+		return
+	}
+}
+
+func (r *poolSnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !strings.Contains(req.ID, "@") {
+		resp.Diagnostics.AddError(
+			"Error Importing Snapshot",
+			fmt.Sprintf("Import ID %q must be a full snapshot name, dataset@name.", req.ID),
+		)
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// read refreshes model from the snapshot with model's ID. It returns false if the
+// snapshot does not exist or could not be read; errors are added to diags.
+func (r *poolSnapshotResource) read(ctx context.Context, model *poolSnapshotResourceModel, diags *diag.Diagnostics) bool {
+	var result poolSnapshotResult
+	err := r.client.Call(ctx, "pool.snapshot.get_instance", []any{model.ID.ValueString(), map[string]any{
+		"extra": map[string]any{"holds": true},
+	}}, &result)
+	if err != nil {
+		if !isNotFound(err) {
+			diags.AddError("Error Reading Snapshot", err.Error())
+		}
+		return false
+	}
+
+	populateSnapshotState(model, &result, r.provider.defaultPool)
+	return true
+}
+
+func (r *poolSnapshotResource) setHold(ctx context.Context, id string, hold, recursive bool) error {
+	method := "pool.snapshot.release"
+	if hold {
+		method = "pool.snapshot.hold"
+	}
+	return r.client.Call(ctx, method, []any{id, map[string]any{"recursive": recursive}}, nil)
+}
+
+// populateSnapshotState updates the model from a pool.snapshot.get_instance result.
+// recursive, exclude and properties are creation options and are kept as they are.
+func populateSnapshotState(model *poolSnapshotResourceModel, result *poolSnapshotResult, defaultPool string) {
+	model.ID = types.StringValue(result.ID)
+	model.Dataset = keepRelativePath(defaultPool, datasetNamePath, model.Dataset, result.Dataset)
+	model.ResolvedDataset = types.StringValue(result.Dataset)
+	model.Name = types.StringValue(result.SnapshotName)
+	_, held := result.Holds[snapshotHoldTag]
+	model.Hold = types.BoolValue(held)
+
+	if n, ok := result.Properties.Referenced.int64Value(); ok {
+		model.Referenced = types.Int64Value(n)
+	} else {
+		model.Referenced = types.Int64Null()
+	}
+	if n, ok := result.Properties.Used.int64Value(); ok {
+		model.Used = types.Int64Value(n)
+	} else {
+		model.Used = types.Int64Null()
+	}
+	model.Createtxg = types.StringValue(result.Properties.Createtxg.stringValue())
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPoolSnapshotResource_basic(t *testing.T) {
+	dsName := testAccPoolName() + "/tf-acc-test-snapshot"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolSnapshotResourceConfig(dsName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_snapshot.test", "id", dsName+"@pre-migration"),
+					resource.TestCheckResourceAttr("truenas_pool_snapshot.test", "hold", "false"),
+					resource.TestCheckResourceAttrSet("truenas_pool_snapshot.test", "referenced"),
+					resource.TestCheckResourceAttrSet("truenas_pool_snapshot.test", "used"),
+				),
+			},
+			{
+				Config: testAccPoolSnapshotResourceConfig(dsName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_snapshot.test", "hold", "true"),
+				),
+			},
+			{
+				ResourceName:            "truenas_pool_snapshot.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"properties", "recursive"},
+			},
+		},
+	})
+}
+
+func testAccPoolSnapshotResourceConfig(dsName string, hold bool) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_dataset" "test" {
+  name                = %q
+  deletion_protection = false
+}
+
+resource "truenas_pool_snapshot" "test" {
+  dataset   = truenas_pool_dataset.test.id
+  name      = "pre-migration"
+  recursive = true
+  hold      = %t
+
+  properties = {
+    "org.terraform:reason" = "acceptance test"
+  }
+}
+`, dsName, hold)
+}
@@ -2,13 +2,12 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -17,33 +16,23 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
-)
-
-var (
-	_ resource.Resource                = (*poolSnapshotTaskResource)(nil)
-	_ resource.ResourceWithConfigure   = (*poolSnapshotTaskResource)(nil)
-	_ resource.ResourceWithImportState = (*poolSnapshotTaskResource)(nil)
 )
 
-type poolSnapshotTaskResource struct {
-	client *client.Client
-}
-
 type poolSnapshotTaskResourceModel struct {
-	ID            types.Int64  `tfsdk:"id"`
-	Dataset       types.String `tfsdk:"dataset"`
-	Recursive     types.Bool   `tfsdk:"recursive"`
-	LifetimeValue types.Int64  `tfsdk:"lifetime_value"`
-	LifetimeUnit  types.String `tfsdk:"lifetime_unit"`
-	Enabled       types.Bool   `tfsdk:"enabled"`
-	Exclude       types.List   `tfsdk:"exclude"`
-	NamingSchema  types.String `tfsdk:"naming_schema"`
-	AllowEmpty    types.Bool   `tfsdk:"allow_empty"`
-	Schedule      types.Object `tfsdk:"schedule"`
+	ID            types.Int64    `tfsdk:"id"`
+	Dataset       types.String   `tfsdk:"dataset"`
+	Recursive     types.Bool     `tfsdk:"recursive"`
+	LifetimeValue types.Int64    `tfsdk:"lifetime_value"`
+	LifetimeUnit  types.String   `tfsdk:"lifetime_unit"`
+	Enabled       types.Bool     `tfsdk:"enabled"`
+	Exclude       types.List     `tfsdk:"exclude"`
+	NamingSchema  types.String   `tfsdk:"naming_schema"`
+	AllowEmpty    types.Bool     `tfsdk:"allow_empty"`
+	Schedule      types.Object   `tfsdk:"schedule"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 type poolSnapshotTaskScheduleModel struct {
@@ -66,18 +55,6 @@ var poolSnapshotTaskScheduleAttrTypes = map[string]attr.Type{
 	"end":    types.StringType,
 }
 
-type poolSnapshotTaskCreateParams struct {
-	Dataset       string                   `json:"dataset"`
-	Recursive     bool                     `json:"recursive"`
-	LifetimeValue int64                    `json:"lifetime_value"`
-	LifetimeUnit  string                   `json:"lifetime_unit"`
-	Enabled       bool                     `json:"enabled"`
-	Exclude       []string                 `json:"exclude"`
-	NamingSchema  string                   `json:"naming_schema"`
-	AllowEmpty    bool                     `json:"allow_empty"`
-	Schedule      poolSnapshotTaskSchedule `json:"schedule"`
-}
-
 type poolSnapshotTaskSchedule struct {
 	Minute string `json:"minute"`
 	Hour   string `json:"hour"`
@@ -102,15 +79,19 @@ type poolSnapshotTaskResult struct {
 }
 
 func NewPoolSnapshotTaskResource() resource.Resource {
-	return &poolSnapshotTaskResource{}
-}
-
-func (r *poolSnapshotTaskResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_pool_snapshot_task"
+	return newCRUDResource(crudSpec[poolSnapshotTaskResourceModel, poolSnapshotTaskResult]{
+		typeName:     "pool_snapshot_task",
+		namespace:    "pool.snapshottask",
+		kind:         "Snapshot Task",
+		schema:       poolSnapshotTaskSchema,
+		params:       poolSnapshotTaskParams,
+		populate:     populatePoolSnapshotTaskState,
+		importLookup: lookupSnapshotTask,
+	})
 }
 
-func (r *poolSnapshotTaskResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func poolSnapshotTaskSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		Description: "Manages a TrueNAS periodic snapshot task.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -135,12 +116,18 @@ func (r *poolSnapshotTaskResource) Schema(_ context.Context, _ resource.SchemaRe
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(2),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
 			"lifetime_unit": schema.StringAttribute{
 				Description: "Unit for snapshot lifetime. Valid values: HOUR, DAY, WEEK, MONTH, YEAR.",
 				Optional:    true,
 				Computed:    true,
 				Default:     stringdefault.StaticString("WEEK"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("HOUR", "DAY", "WEEK", "MONTH", "YEAR"),
+				},
 			},
 			"enabled": schema.BoolAttribute{
 				Description: "Whether the snapshot task is enabled.",
@@ -186,187 +173,90 @@ func (r *poolSnapshotTaskResource) Schema(_ context.Context, _ resource.SchemaRe
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("00"),
+						Validators: []validator.String{
+							validCronField("minute"),
+						},
 					},
 					"hour": schema.StringAttribute{
 						Description: "Hour field (0-23, *, or cron expression).",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("*"),
+						Validators: []validator.String{
+							validCronField("hour"),
+						},
 					},
 					"dom": schema.StringAttribute{
 						Description: "Day of month field (1-31, *, or cron expression).",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("*"),
+						Validators: []validator.String{
+							validCronField("dom"),
+						},
 					},
 					"month": schema.StringAttribute{
 						Description: "Month field (1-12, *, or cron expression).",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("*"),
+						Validators: []validator.String{
+							validCronField("month"),
+						},
 					},
 					"dow": schema.StringAttribute{
-						Description: "Day of week field (0-6, *, or cron expression).",
+						Description: "Day of week field (0-7, where 0 and 7 are Sunday, *, or cron expression).",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("*"),
+						Validators: []validator.String{
+							validCronField("dow"),
+						},
 					},
 					"begin": schema.StringAttribute{
 						Description: "Start time of the allowed window (HH:MM).",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("00:00"),
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(timeOfDayRegexp, "must be a time of day in HH:MM format"),
+						},
 					},
 					"end": schema.StringAttribute{
 						Description: "End time of the allowed window (HH:MM).",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("23:59"),
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(timeOfDayRegexp, "must be a time of day in HH:MM format"),
+						},
 					},
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *poolSnapshotTaskResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
-	}
-
-	r.client = c
-}
-
-func (r *poolSnapshotTaskResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan poolSnapshotTaskResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var sched poolSnapshotTaskScheduleModel
-	resp.Diagnostics.Append(plan.Schedule.As(ctx, &sched, basetypes.ObjectAsOptions{})...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var exclude []string
-	if !plan.Exclude.IsNull() {
-		resp.Diagnostics.Append(plan.Exclude.ElementsAs(ctx, &exclude, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-	} else {
-		exclude = []string{}
-	}
-
-	params := poolSnapshotTaskCreateParams{
-		Dataset:       plan.Dataset.ValueString(),
-		Recursive:     plan.Recursive.ValueBool(),
-		LifetimeValue: plan.LifetimeValue.ValueInt64(),
-		LifetimeUnit:  plan.LifetimeUnit.ValueString(),
-		Enabled:       plan.Enabled.ValueBool(),
-		Exclude:       exclude,
-		NamingSchema:  plan.NamingSchema.ValueString(),
-		AllowEmpty:    plan.AllowEmpty.ValueBool(),
-		Schedule: poolSnapshotTaskSchedule{
-			Minute: sched.Minute.ValueString(),
-			Hour:   sched.Hour.ValueString(),
-			Dom:    sched.Dom.ValueString(),
-			Month:  sched.Month.ValueString(),
-			Dow:    sched.Dow.ValueString(),
-			Begin:  sched.Begin.ValueString(),
-			End:    sched.End.ValueString(),
-		},
-	}
-
-	var result poolSnapshotTaskResult
-	err := r.client.Call(ctx, "pool.snapshottask.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Snapshot Task", err.Error())
-		return
-	}
-
-	resp.Diagnostics.Append(setPoolSnapshotTaskResourceState(&plan, &result)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *poolSnapshotTaskResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state poolSnapshotTaskResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var result poolSnapshotTaskResult
-	err := r.client.Call(ctx, "pool.snapshottask.get_instance", []any{state.ID.ValueInt64()}, &result)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
-			resp.State.RemoveResource(ctx)
-			return
-		}
-		resp.Diagnostics.AddError("Error Reading Snapshot Task", err.Error())
-		return
-	}
-
-	resp.Diagnostics.Append(setPoolSnapshotTaskResourceState(&state, &result)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-}
-
-func (r *poolSnapshotTaskResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan poolSnapshotTaskResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var state poolSnapshotTaskResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
+func poolSnapshotTaskParams(ctx context.Context, plan, _ *poolSnapshotTaskResourceModel, diags *diag.Diagnostics) map[string]any {
 	var sched poolSnapshotTaskScheduleModel
-	resp.Diagnostics.Append(plan.Schedule.As(ctx, &sched, basetypes.ObjectAsOptions{})...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+	diags.Append(plan.Schedule.As(ctx, &sched, basetypes.ObjectAsOptions{})...)
 
-	var exclude []string
+	exclude := []string{}
 	if !plan.Exclude.IsNull() {
-		resp.Diagnostics.Append(plan.Exclude.ElementsAs(ctx, &exclude, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-	} else {
-		exclude = []string{}
+		diags.Append(plan.Exclude.ElementsAs(ctx, &exclude, false)...)
 	}
 
-	params := poolSnapshotTaskCreateParams{
-		Dataset:       plan.Dataset.ValueString(),
-		Recursive:     plan.Recursive.ValueBool(),
-		LifetimeValue: plan.LifetimeValue.ValueInt64(),
-		LifetimeUnit:  plan.LifetimeUnit.ValueString(),
-		Enabled:       plan.Enabled.ValueBool(),
-		Exclude:       exclude,
-		NamingSchema:  plan.NamingSchema.ValueString(),
-		AllowEmpty:    plan.AllowEmpty.ValueBool(),
-		Schedule: poolSnapshotTaskSchedule{
+	return map[string]any{
+		"dataset":        plan.Dataset.ValueString(),
+		"recursive":      plan.Recursive.ValueBool(),
+		"lifetime_value": plan.LifetimeValue.ValueInt64(),
+		"lifetime_unit":  plan.LifetimeUnit.ValueString(),
+		"enabled":        plan.Enabled.ValueBool(),
+		"exclude":        exclude,
+		"naming_schema":  plan.NamingSchema.ValueString(),
+		"allow_empty":    plan.AllowEmpty.ValueBool(),
+		"schedule": poolSnapshotTaskSchedule{
 			Minute: sched.Minute.ValueString(),
 			Hour:   sched.Hour.ValueString(),
 			Dom:    sched.Dom.ValueString(),
@@ -376,51 +266,9 @@ func (r *poolSnapshotTaskResource) Update(ctx context.Context, req resource.Upda
 			End:    sched.End.ValueString(),
 		},
 	}
-
-	var result poolSnapshotTaskResult
-	err := r.client.Call(ctx, "pool.snapshottask.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating Snapshot Task", err.Error())
-		return
-	}
-
-	resp.Diagnostics.Append(setPoolSnapshotTaskResourceState(&plan, &result)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *poolSnapshotTaskResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state poolSnapshotTaskResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	err := r.client.Call(ctx, "pool.snapshottask.delete", []any{state.ID.ValueInt64()}, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting Snapshot Task", err.Error())
-		return
-	}
-}
-
-func (r *poolSnapshotTaskResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing Snapshot Task",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.Int64Value(id))...)
 }
 
-func setPoolSnapshotTaskResourceState(model *poolSnapshotTaskResourceModel, result *poolSnapshotTaskResult) diag.Diagnostics {
-	var diags diag.Diagnostics
-
+func populatePoolSnapshotTaskState(_ context.Context, model *poolSnapshotTaskResourceModel, result *poolSnapshotTaskResult, diags *diag.Diagnostics) {
 	model.ID = types.Int64Value(result.ID)
 	model.Dataset = types.StringValue(result.Dataset)
 	model.Recursive = types.BoolValue(result.Recursive)
@@ -453,6 +301,4 @@ func setPoolSnapshotTaskResourceState(model *poolSnapshotTaskResourceModel, resu
 	})
 	diags.Append(d...)
 	model.Schedule = scheduleValue
-
-	return diags
 }
@@ -38,7 +38,7 @@ func testAccCheckPoolSnapshotTaskDestroy(s *terraform.State) error {
 		var result poolSnapshotTaskResult
 		err = c.Call(ctx, "pool.snapshottask.get_instance", []any{id}, &result)
 		if err != nil {
-			if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+			if isNotFound(err) {
 				continue
 			}
 			return fmt.Errorf("querying snapshot task: %s", err)
@@ -80,6 +80,12 @@ func TestAccPoolSnapshotTaskResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "truenas_pool_snapshot_task.test",
+				ImportState:       true,
+				ImportStateId:     "tank/snap-test",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -175,7 +181,8 @@ func TestAccPoolSnapshotTaskResource_partialSchedule(t *testing.T) {
 func testAccPoolSnapshotTaskResourceConfig_basic() string {
 	return testAccProviderConfig() + `
 resource "truenas_pool_dataset" "snap_test" {
-  name = "tank/snap-test"
+  name                = "tank/snap-test"
+  deletion_protection = false
 }
 
 resource "truenas_pool_snapshot_task" "test" {
@@ -187,7 +194,8 @@ resource "truenas_pool_snapshot_task" "test" {
 func testAccPoolSnapshotTaskResourceConfig_updated() string {
 	return testAccProviderConfig() + `
 resource "truenas_pool_dataset" "snap_test" {
-  name = "tank/snap-test"
+  name                = "tank/snap-test"
+  deletion_protection = false
 }
 
 resource "truenas_pool_snapshot_task" "test" {
@@ -211,12 +219,14 @@ resource "truenas_pool_snapshot_task" "test" {
 func testAccPoolSnapshotTaskResourceConfig_allFields() string {
 	return testAccProviderConfig() + `
 resource "truenas_pool_dataset" "snap_test" {
-  name = "tank/snap-test"
+  name                = "tank/snap-test"
+  deletion_protection = false
 }
 
 resource "truenas_pool_dataset" "snap_test_child" {
-  name       = "tank/snap-test/child"
-  depends_on = [truenas_pool_dataset.snap_test]
+  name                = "tank/snap-test/child"
+  deletion_protection = false
+  depends_on          = [truenas_pool_dataset.snap_test]
 }
 
 resource "truenas_pool_snapshot_task" "test" {
@@ -244,7 +254,8 @@ resource "truenas_pool_snapshot_task" "test" {
 func testAccPoolSnapshotTaskResourceConfig_partialSchedule() string {
 	return testAccProviderConfig() + `
 resource "truenas_pool_dataset" "snap_test" {
-  name = "tank/snap-test"
+  name                = "tank/snap-test"
+  deletion_protection = false
 }
 
 resource "truenas_pool_snapshot_task" "test" {
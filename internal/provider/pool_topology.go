@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// poolVdev is one vdev of a pool topology. Disks are referenced by serial number or
+// by TrueNAS disk identifier, which, unlike device names, do not change across
+// reboots. A STRIPE vdev stands for one single-disk vdev per disk.
+type poolVdev struct {
+	Type  string   `tfsdk:"type"`
+	Disks []string `tfsdk:"disks"`
+}
+
+// poolTopology is the topology attribute of truenas_pool.
+type poolTopology struct {
+	Data    []poolVdev `tfsdk:"data"`
+	Log     []poolVdev `tfsdk:"log"`
+	Cache   []poolVdev `tfsdk:"cache"`
+	Special []poolVdev `tfsdk:"special"`
+	Dedup   []poolVdev `tfsdk:"dedup"`
+	Spares  []string   `tfsdk:"spares"`
+}
+
+var poolVdevAttrTypes = map[string]attr.Type{
+	"type":  types.StringType,
+	"disks": types.ListType{ElemType: types.StringType},
+}
+
+var poolTopologyAttrTypes = map[string]attr.Type{
+	"data":    types.ListType{ElemType: types.ObjectType{AttrTypes: poolVdevAttrTypes}},
+	"log":     types.ListType{ElemType: types.ObjectType{AttrTypes: poolVdevAttrTypes}},
+	"cache":   types.ListType{ElemType: types.ObjectType{AttrTypes: poolVdevAttrTypes}},
+	"special": types.ListType{ElemType: types.ObjectType{AttrTypes: poolVdevAttrTypes}},
+	"dedup":   types.ListType{ElemType: types.ObjectType{AttrTypes: poolVdevAttrTypes}},
+	"spares":  types.ListType{ElemType: types.StringType},
+}
+
+// poolVdevClass is a vdev category of a topology: its name in pool.create and
+// pool.update, and accessors for the configuration and pool.query result.
+type poolVdevClass struct {
+	name   string
+	vdevs  func(t *poolTopology) *[]poolVdev
+	result func(r *poolTopologyResult) []poolVdevResult
+}
+
+var poolVdevClasses = []poolVdevClass{
+	{"data", func(t *poolTopology) *[]poolVdev { return &t.Data }, func(r *poolTopologyResult) []poolVdevResult { return r.Data }},
+	{"log", func(t *poolTopology) *[]poolVdev { return &t.Log }, func(r *poolTopologyResult) []poolVdevResult { return r.Log }},
+	{"cache", func(t *poolTopology) *[]poolVdev { return &t.Cache }, func(r *poolTopologyResult) []poolVdevResult { return r.Cache }},
+	{"special", func(t *poolTopology) *[]poolVdev { return &t.Special }, func(r *poolTopologyResult) []poolVdevResult { return r.Special }},
+	{"dedup", func(t *poolTopology) *[]poolVdev { return &t.Dedup }, func(r *poolTopologyResult) []poolVdevResult { return r.Dedup }},
+}
+
+type poolVdevResult struct {
+	Type     string           `json:"type"`
+	Disk     *string          `json:"disk"`
+	Children []poolVdevResult `json:"children"`
+}
+
+type poolTopologyResult struct {
+	Data    []poolVdevResult `json:"data"`
+	Log     []poolVdevResult `json:"log"`
+	Cache   []poolVdevResult `json:"cache"`
+	Special []poolVdevResult `json:"special"`
+	Dedup   []poolVdevResult `json:"dedup"`
+	Spare   []poolVdevResult `json:"spare"`
+}
+
+// diskResult is an entry of disk.query.
+type diskResult struct {
+	Name       string `json:"name"`
+	Serial     string `json:"serial"`
+	Identifier string `json:"identifier"`
+}
+
+// poolDiskNames maps the serial numbers and identifiers of disks to their device
+// names, as expected by pool.create and pool.update.
+func poolDiskNames(disks []diskResult) map[string]string {
+	names := map[string]string{}
+	for _, d := range disks {
+		if d.Serial != "" {
+			names[d.Serial] = d.Name
+		}
+		if d.Identifier != "" {
+			names[d.Identifier] = d.Name
+		}
+	}
+	return names
+}
+
+// topologyParams converts t to the topology parameter of pool.create and pool.update.
+// Empty categories are omitted.
+func topologyParams(t poolTopology, names map[string]string) (map[string]any, error) {
+	resolve := func(refs []string) ([]string, error) {
+		devices := make([]string, len(refs))
+		for i, ref := range refs {
+			name, ok := names[ref]
+			if !ok {
+				return nil, fmt.Errorf("no disk has the serial number or identifier %q", ref)
+			}
+			devices[i] = name
+		}
+		return devices, nil
+	}
+
+	params := map[string]any{}
+	for _, class := range poolVdevClasses {
+		vdevs := *class.vdevs(&t)
+		if len(vdevs) == 0 {
+			continue
+		}
+		list := make([]map[string]any, len(vdevs))
+		for i, v := range vdevs {
+			devices, err := resolve(v.Disks)
+			if err != nil {
+				return nil, fmt.Errorf("%s vdev %d: %w", class.name, i, err)
+			}
+			list[i] = map[string]any{"type": v.Type, "disks": devices}
+		}
+		params[class.name] = list
+	}
+	if len(t.Spares) > 0 {
+		devices, err := resolve(t.Spares)
+		if err != nil {
+			return nil, fmt.Errorf("spares: %w", err)
+		}
+		params["spares"] = devices
+	}
+	return params, nil
+}
+
+// topologyFromResult converts the topology of a pool.query result. Disks are
+// referenced the way prior (the configuration or state, possibly empty) references
+// them, falling back to the serial number, then the identifier. Single-disk vdevs of
+// a category are merged into one STRIPE vdev, and the configured order of disks
+// within a vdev is kept.
+func topologyFromResult(r poolTopologyResult, disks []diskResult, prior poolTopology) poolTopology {
+	byName := map[string]diskResult{}
+	for _, d := range disks {
+		byName[d.Name] = d
+	}
+	priorRefs := map[string]bool{}
+	for _, class := range poolVdevClasses {
+		for _, v := range *class.vdevs(&prior) {
+			for _, ref := range v.Disks {
+				priorRefs[ref] = true
+			}
+		}
+	}
+	for _, ref := range prior.Spares {
+		priorRefs[ref] = true
+	}
+
+	ref := func(name string) string {
+		d, ok := byName[name]
+		switch {
+		case !ok:
+			return name
+		case d.Serial != "" && priorRefs[d.Serial]:
+			return d.Serial
+		case d.Identifier != "" && priorRefs[d.Identifier]:
+			return d.Identifier
+		case d.Serial != "":
+			return d.Serial
+		case d.Identifier != "":
+			return d.Identifier
+		default:
+			return name
+		}
+	}
+
+	var leaves func(v poolVdevResult) []string
+	leaves = func(v poolVdevResult) []string {
+		if len(v.Children) == 0 {
+			if v.Disk == nil {
+				return nil
+			}
+			return []string{ref(*v.Disk)}
+		}
+		var refs []string
+		for _, child := range v.Children {
+			refs = append(refs, leaves(child)...)
+		}
+		return refs
+	}
+
+	var t poolTopology
+	for _, class := range poolVdevClasses {
+		var vdevs []poolVdev
+		stripe := -1
+		for _, v := range class.result(&r) {
+			if v.Type == "DISK" {
+				if stripe < 0 {
+					vdevs = append(vdevs, poolVdev{Type: "STRIPE"})
+					stripe = len(vdevs) - 1
+				}
+				vdevs[stripe].Disks = append(vdevs[stripe].Disks, leaves(v)...)
+				continue
+			}
+			vdevs = append(vdevs, poolVdev{Type: v.Type, Disks: leaves(v)})
+		}
+
+		priorVdevs := *class.vdevs(&prior)
+		for i := range vdevs {
+			if i < len(priorVdevs) && priorVdevs[i].Type == vdevs[i].Type && sameDisks(priorVdevs[i].Disks, vdevs[i].Disks) {
+				vdevs[i].Disks = priorVdevs[i].Disks
+			}
+		}
+		*class.vdevs(&t) = vdevs
+	}
+
+	for _, v := range r.Spare {
+		t.Spares = append(t.Spares, leaves(v)...)
+	}
+	if sameDisks(prior.Spares, t.Spares) {
+		t.Spares = prior.Spares
+	}
+
+	return t
+}
+
+// topologyAdditions returns the vdevs and spares that must be added to a pool with
+// the current topology to reach the planned one. Only additions are supported:
+// new vdevs at the end of a category, new disks in a STRIPE vdev and new spares.
+func topologyAdditions(current, planned poolTopology) (poolTopology, error) {
+	var added poolTopology
+	for _, class := range poolVdevClasses {
+		cur, plan := *class.vdevs(&current), *class.vdevs(&planned)
+		if len(plan) < len(cur) {
+			return poolTopology{}, fmt.Errorf("removing %s vdevs is not supported", class.name)
+		}
+
+		var vdevs []poolVdev
+		for i, v := range cur {
+			p := plan[i]
+			if p.Type != v.Type {
+				return poolTopology{}, fmt.Errorf("%s vdev %d: changing the type from %s to %s is not supported", class.name, i, v.Type, p.Type)
+			}
+			extra := disksNotIn(p.Disks, v.Disks)
+			if len(disksNotIn(v.Disks, p.Disks)) > 0 || (len(extra) > 0 && v.Type != "STRIPE") {
+				return poolTopology{}, fmt.Errorf("%s vdev %d: changing the disks of an existing vdev is not supported; only disks of STRIPE vdevs can be added", class.name, i)
+			}
+			if len(extra) > 0 {
+				vdevs = append(vdevs, poolVdev{Type: "STRIPE", Disks: extra})
+			}
+		}
+		vdevs = append(vdevs, plan[len(cur):]...)
+		*class.vdevs(&added) = vdevs
+	}
+
+	if len(disksNotIn(current.Spares, planned.Spares)) > 0 {
+		return poolTopology{}, fmt.Errorf("removing spares is not supported")
+	}
+	added.Spares = disksNotIn(planned.Spares, current.Spares)
+
+	return added, nil
+}
+
+// isEmpty reports whether t has no vdevs and no spares.
+func (t poolTopology) isEmpty() bool {
+	for _, class := range poolVdevClasses {
+		if len(*class.vdevs(&t)) > 0 {
+			return false
+		}
+	}
+	return len(t.Spares) == 0
+}
+
+// disksNotIn returns the disks of a that are not in b.
+func disksNotIn(a, b []string) []string {
+	var out []string
+	for _, d := range a {
+		if !slices.Contains(b, d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func sameDisks(a, b []string) bool {
+	return len(a) == len(b) && len(disksNotIn(a, b)) == 0
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func strPtr(s string) *string { return &s }
+
+var testPoolDisks = []diskResult{
+	{Name: "sda", Serial: "S1", Identifier: "{serial_lunid}S1_5000"},
+	{Name: "sdb", Serial: "S2", Identifier: "{serial_lunid}S2_5000"},
+	{Name: "sdc", Serial: "S3", Identifier: "{serial_lunid}S3_5000"},
+	{Name: "sdd", Serial: "S4", Identifier: "{serial_lunid}S4_5000"},
+	{Name: "nvme0n1", Identifier: "{uuid}abcd"},
+}
+
+func TestTopologyParams(t *testing.T) {
+	topology := poolTopology{
+		Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S1", "{serial_lunid}S2_5000"}}},
+		Log:    []poolVdev{{Type: "STRIPE", Disks: []string{"{uuid}abcd"}}},
+		Spares: []string{"S3"},
+	}
+
+	got, err := topologyParams(topology, poolDiskNames(testPoolDisks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"data":   []map[string]any{{"type": "MIRROR", "disks": []string{"sda", "sdb"}}},
+		"log":    []map[string]any{{"type": "STRIPE", "disks": []string{"nvme0n1"}}},
+		"spares": []string{"sdc"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	_, err = topologyParams(poolTopology{Data: []poolVdev{{Type: "STRIPE", Disks: []string{"S9"}}}}, poolDiskNames(testPoolDisks))
+	if err == nil || !strings.Contains(err.Error(), `"S9"`) {
+		t.Errorf("expected an unknown disk error, got %v", err)
+	}
+}
+
+func TestTopologyFromResult(t *testing.T) {
+	result := poolTopologyResult{
+		Data: []poolVdevResult{
+			{Type: "MIRROR", Children: []poolVdevResult{
+				{Type: "DISK", Disk: strPtr("sdb")},
+				{Type: "DISK", Disk: strPtr("sda")},
+			}},
+		},
+		Cache: []poolVdevResult{
+			{Type: "DISK", Disk: strPtr("sdc")},
+			{Type: "DISK", Disk: strPtr("nvme0n1")},
+		},
+		Spare: []poolVdevResult{{Type: "DISK", Disk: strPtr("sdd")}},
+	}
+
+	t.Run("import", func(t *testing.T) {
+		got := topologyFromResult(result, testPoolDisks, poolTopology{})
+		want := poolTopology{
+			Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S2", "S1"}}},
+			Cache:  []poolVdev{{Type: "STRIPE", Disks: []string{"S3", "{uuid}abcd"}}},
+			Spares: []string{"S4"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("prior references and order", func(t *testing.T) {
+		prior := poolTopology{
+			Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"{serial_lunid}S1_5000", "S2"}}},
+			Cache:  []poolVdev{{Type: "STRIPE", Disks: []string{"{uuid}abcd", "S3"}}},
+			Spares: []string{"{serial_lunid}S4_5000"},
+		}
+		got := topologyFromResult(result, testPoolDisks, prior)
+		if !reflect.DeepEqual(got, prior) {
+			t.Errorf("got %#v, want %#v", got, prior)
+		}
+	})
+}
+
+func TestTopologyAdditions(t *testing.T) {
+	current := poolTopology{
+		Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S1", "S2"}}},
+		Cache:  []poolVdev{{Type: "STRIPE", Disks: []string{"S3"}}},
+		Spares: []string{"S4"},
+	}
+
+	tests := []struct {
+		name    string
+		planned poolTopology
+		want    poolTopology
+		wantErr string
+	}{
+		{
+			name:    "unchanged",
+			planned: current,
+		},
+		{
+			name: "new vdev and spare",
+			planned: poolTopology{
+				Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S1", "S2"}}, {Type: "MIRROR", Disks: []string{"S5", "S6"}}},
+				Cache:  []poolVdev{{Type: "STRIPE", Disks: []string{"S3"}}},
+				Spares: []string{"S4", "S7"},
+			},
+			want: poolTopology{
+				Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S5", "S6"}}},
+				Spares: []string{"S7"},
+			},
+		},
+		{
+			name: "stripe growth",
+			planned: poolTopology{
+				Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S1", "S2"}}},
+				Cache:  []poolVdev{{Type: "STRIPE", Disks: []string{"S3", "S5"}}},
+				Spares: []string{"S4"},
+			},
+			want: poolTopology{
+				Cache: []poolVdev{{Type: "STRIPE", Disks: []string{"S5"}}},
+			},
+		},
+		{
+			name: "vdev removed",
+			planned: poolTopology{
+				Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S1", "S2"}}},
+				Spares: []string{"S4"},
+			},
+			wantErr: "removing cache vdevs",
+		},
+		{
+			name: "mirror widened",
+			planned: poolTopology{
+				Data:   []poolVdev{{Type: "MIRROR", Disks: []string{"S1", "S2", "S5"}}},
+				Cache:  []poolVdev{{Type: "STRIPE", Disks: []string{"S3"}}},
+				Spares: []string{"S4"},
+			},
+			wantErr: "data vdev 0",
+		},
+		{
+			name: "type changed",
+			planned: poolTopology{
+				Data:   []poolVdev{{Type: "RAIDZ1", Disks: []string{"S1", "S2"}}},
+				Cache:  []poolVdev{{Type: "STRIPE", Disks: []string{"S3"}}},
+				Spares: []string{"S4"},
+			},
+			wantErr: "from MIRROR to RAIDZ1",
+		},
+		{
+			name: "spare removed",
+			planned: poolTopology{
+				Data:  []poolVdev{{Type: "MIRROR", Disks: []string{"S1", "S2"}}},
+				Cache: []poolVdev{{Type: "STRIPE", Disks: []string{"S3"}}},
+			},
+			wantErr: "removing spares",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := topologyAdditions(current, tt.planned)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+			if got.isEmpty() != tt.want.isEmpty() {
+				t.Errorf("isEmpty() = %v", got.isEmpty())
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+var (
+	_ resource.Resource                = (*poolZvolResource)(nil)
+	_ resource.ResourceWithConfigure   = (*poolZvolResource)(nil)
+	_ resource.ResourceWithImportState = (*poolZvolResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*poolZvolResource)(nil)
+)
+
+type poolZvolResource struct {
+	client   *client.Client
+	provider *providerData
+}
+
+type poolZvolResourceModel struct {
+	datasetPropertiesModel
+
+	ID                 types.String   `tfsdk:"id"`
+	Name               types.String   `tfsdk:"name"`
+	ResolvedName       types.String   `tfsdk:"resolved_name"`
+	Pool               types.String   `tfsdk:"pool"`
+	Volsize            sizeValue      `tfsdk:"volsize"`
+	Volblocksize       types.String   `tfsdk:"volblocksize"`
+	Sparse             types.Bool     `tfsdk:"sparse"`
+	CreateAncestors    types.Bool     `tfsdk:"create_ancestors"`
+	DevicePath         types.String   `tfsdk:"device_path"`
+	Encrypted          types.Bool     `tfsdk:"encrypted"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+type poolZvolResult struct {
+	datasetPropertiesResult
+
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	Pool           string      `json:"pool"`
+	Type           string      `json:"type"`
+	Encrypted      bool        `json:"encrypted"`
+	Volsize        zfsProperty `json:"volsize"`
+	Volblocksize   zfsProperty `json:"volblocksize"`
+	Refreservation zfsProperty `json:"refreservation"`
+}
+
+func NewPoolZvolResource() resource.Resource {
+	return &poolZvolResource{}
+}
+
+func (r *poolZvolResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pool_zvol"
+}
+
+func (r *poolZvolResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a TrueNAS ZFS volume (zvol), e.g. to back an iSCSI extent or an NVMe-oF namespace.\n\n" +
+			"~> **Note:** As with `truenas_pool_dataset`, only ZFS properties with a LOCAL source are stored in state. " +
+			"Setting an attribute to null causes it to revert to its inherited or default value.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The unique identifier of the zvol (same as name).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Full zvol path including pool, e.g. \"tank/vols/lun0\", or a path relative to the provider's default_pool, e.g. \"./vols/lun0\". Cannot be changed after creation.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"resolved_name": resolvedPathAttribute("name", "tank/vols/lun0"),
+			"pool": schema.StringAttribute{
+				Description: "The pool name, extracted from the zvol path.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"volsize": schema.StringAttribute{
+				Description: "Size of the zvol, as bytes or with a unit such as \"100GiB\". Can be increased in place; shrinking is rejected at plan time.",
+				Required:    true,
+				CustomType:  sizeType{},
+				Validators: []validator.String{
+					sizeAtLeast(1),
+				},
+			},
+			"volblocksize": schema.StringAttribute{
+				Description: "Block size: 512, 1K, 2K, 4K, 8K, 16K, 32K, 64K or 128K. Defaults to the server's default. Cannot be changed after creation.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("512", "1K", "2K", "4K", "8K", "16K", "32K", "64K", "128K"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sparse": schema.BoolAttribute{
+				Description: "Create a thin-provisioned zvol, without reserving its size in the pool. Cannot be changed afterwards; read back from the zvol's refreservation, so imported zvols keep their value. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"comments": schema.StringAttribute{
+				Description: "User-provided comments for the zvol. Null means inherited from parent.",
+				Optional:    true,
+			},
+			"sync": schema.StringAttribute{
+				Description: "Sync mode: STANDARD, ALWAYS, or DISABLED. Null means inherited from parent.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("STANDARD", "ALWAYS", "DISABLED"),
+				},
+			},
+			"compression": schema.StringAttribute{
+				Description: "Compression algorithm: OFF, LZ4, GZIP, ZSTD, etc. Checked against the algorithms supported by the server at plan time. Null means inherited from parent.",
+				Optional:    true,
+			},
+			"readonly": schema.StringAttribute{
+				Description: "Read-only mode: ON or OFF. Null means inherited from parent.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "OFF"),
+				},
+			},
+			"deduplication": schema.StringAttribute{
+				Description: "Deduplication: ON, VERIFY, or OFF. Null means inherited from parent.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ON", "VERIFY", "OFF"),
+				},
+			},
+			"checksum": schema.StringAttribute{
+				Description: "Checksum algorithm: ON, FLETCHER2, FLETCHER4, SHA256, SHA512, SKEIN, BLAKE3. Checked against the algorithms supported by the server at plan time. Null means inherited from parent.",
+				Optional:    true,
+			},
+			"copies": schema.Int64Attribute{
+				Description: "Number of data copies: 1, 2, or 3. Null means inherited from parent.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 3),
+				},
+			},
+			"create_ancestors": schema.BoolAttribute{
+				Description: "Create ancestor datasets if they don't exist. Only used during creation, not stored in state.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"device_path": schema.StringAttribute{
+				Description: "The zvol path used by iSCSI extents and NVMe-oF namespaces, e.g. zvol/tank/vols/lun0.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"encrypted": schema.BoolAttribute{
+				Description: "Whether the zvol is encrypted.",
+				Computed:    true,
+			},
+			"deletion_protection": deletionProtectionAttribute("zvol"),
+			"user_properties":     userPropertiesAttribute("zvol"),
+			"ownership_tag":       ownershipTagAttribute("zvol", "in its "+ownershipProperty+" ZFS user property"),
+			"timeouts":            timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
+		},
+	}
+}
+
+func (r *poolZvolResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.provider = data
+}
+
+// ModifyPlan checks server-dependent choices, as for datasets, and rejects shrinking
+// the zvol, which would destroy the data at its end.
+func (r *poolZvolResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state poolZvolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateServerChoice(ctx, r.client, "pool.dataset.compression_choices", []any{}, path.Root("compression"), plan.Compression, state.Compression, &resp.Diagnostics)
+	validateServerChoice(ctx, r.client, "pool.dataset.checksum_choices", []any{}, path.Root("checksum"), plan.Checksum, state.Checksum, &resp.Diagnostics)
+
+	checkRelativePath(r.provider, path.Root("name"), plan.Name, &resp.Diagnostics)
+	ignoreEquivalentReplace(r.provider, datasetNamePath, path.Root("name"), plan.Name, state.Name, resp)
+	planResolvedPath(ctx, r.provider, datasetNamePath, path.Root("resolved_name"), plan.Name, resp)
+
+	if !req.State.Raw.IsNull() && !plan.Volsize.IsUnknown() && plan.Volsize.ValueBytes() < state.Volsize.ValueBytes() {
+		resp.Diagnostics.AddAttributeError(path.Root("volsize"), "Zvol Cannot Shrink",
+			fmt.Sprintf("volsize of zvol %q cannot be reduced from %s to %s: shrinking a zvol destroys the data at its end. Replace the zvol instead.",
+				state.ID.ValueString(), formatSize(state.Volsize.ValueBytes()), formatSize(plan.Volsize.ValueBytes())))
+	}
+
+	planOwnershipTag(ctx, r.provider, req, resp)
+}
+
+func (r *poolZvolResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan poolZvolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	params := map[string]any{
+		"name":    resolvePoolPath(r.provider.defaultPool, datasetNamePath, plan.Name.ValueString()),
+		"type":    "VOLUME",
+		"volsize": plan.Volsize.ValueBytes(),
+		"sparse":  plan.Sparse.ValueBool(),
+	}
+
+	if !plan.Volblocksize.IsNull() && !plan.Volblocksize.IsUnknown() {
+		params["volblocksize"] = plan.Volblocksize.ValueString()
+	}
+	setDatasetPropertiesCreateParams(params, &plan.datasetPropertiesModel)
+
+	if !plan.CreateAncestors.IsNull() && plan.CreateAncestors.ValueBool() {
+		params["create_ancestors"] = true
+	}
+
+	var result poolZvolResult
+	err := r.client.Call(ctx, "pool.dataset.create", []any{params}, &result)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Zvol", err.Error())
+		return
+	}
+
+	populateZvolState(&plan, &result, r.provider.defaultPool)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolZvolResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state poolZvolResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var result poolZvolResult
+	err := r.client.Call(ctx, "pool.dataset.get_instance", []any{state.ID.ValueString()}, &result)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error Reading Zvol", err.Error())
+		return
+	}
+	if result.Type != "VOLUME" {
+		resp.Diagnostics.AddError("Error Reading Zvol", fmt.Sprintf("%q is a %s dataset, not a zvol; manage it with truenas_pool_dataset.", result.Name, result.Type))
+		return
+	}
+
+	populateZvolState(&state, &result, r.provider.defaultPool)
+	state.DeletionProtection = deletionProtectionValue(state.DeletionProtection)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *poolZvolResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state poolZvolResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	params := map[string]any{}
+
+	if plan.Volsize.ValueBytes() != state.Volsize.ValueBytes() {
+		params["volsize"] = plan.Volsize.ValueBytes()
+	}
+	setDatasetPropertiesUpdateParams(params, &plan.datasetPropertiesModel, &state.datasetPropertiesModel)
+
+	var result poolZvolResult
+	err := r.client.Call(ctx, "pool.dataset.update", []any{state.ID.ValueString(), params}, &result)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Zvol", err.Error())
+		return
+	}
+
+	populateZvolState(&plan, &result, r.provider.defaultPool)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *poolZvolResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state poolZvolResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !checkDeletionProtection("zvol", state.ID.ValueString(), state.DeletionProtection, &resp.Diagnostics) {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	deleteOpts := map[string]any{
+		"recursive": false,
+		"force":     false,
+	}
+
+	err := r.client.Call(ctx, "pool.dataset.delete", []any{state.ID.ValueString(), deleteOpts}, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Zvol", err.Error())
+		return
+	}
+}
+
+func (r *poolZvolResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The ownership check reads the object, which is skipped before the provider is
+	// configured.
+	if r.client != nil {
+		var result poolZvolResult
+		if err := r.client.Call(ctx, "pool.dataset.get_instance", []any{req.ID}, &result); err == nil && result.UserProperties.Workspace != nil {
+			checkImportOwnership(r.provider, "Zvol", result.Name, readStringProperty(result.UserProperties.Workspace).ValueString(), &resp.Diagnostics)
+		}
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// populateZvolState updates the Terraform resource model from a TrueNAS API result,
+// storing only LOCAL-sourced properties like populateDatasetState. volsize and
+// volblocksize are always stored, as they belong to the zvol itself.
+func populateZvolState(model *poolZvolResourceModel, result *poolZvolResult, defaultPool string) {
+	model.ID = types.StringValue(result.ID)
+	model.Name = keepRelativePath(defaultPool, datasetNamePath, model.Name, result.Name)
+	model.ResolvedName = types.StringValue(result.Name)
+	model.Pool = types.StringValue(result.Pool)
+	model.DevicePath = types.StringValue("zvol/" + result.Name)
+	model.Encrypted = types.BoolValue(result.Encrypted)
+
+	if n, ok := result.Volsize.int64Value(); ok {
+		model.Volsize = sizeBytes(n)
+	}
+	model.Volblocksize = types.StringValue(result.Volblocksize.stringValue())
+
+	// A zvol without a refreservation is sparse. When the server omits the property,
+	// the prior value is kept.
+	if result.Refreservation.Value != "" {
+		n, _ := result.Refreservation.int64Value()
+		model.Sparse = types.BoolValue(n == 0)
+	} else if model.Sparse.IsNull() {
+		model.Sparse = types.BoolValue(false)
+	}
+
+	populateDatasetProperties(&model.datasetPropertiesModel, &result.datasetPropertiesResult)
+}
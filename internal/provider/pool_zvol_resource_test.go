@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPoolZvolResource_basic(t *testing.T) {
+	name := testAccPoolName() + "/tf-acc-test-zvol"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoolZvolResourceConfig(name, "1GiB"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_zvol.test", "id", name),
+					resource.TestCheckResourceAttr("truenas_pool_zvol.test", "volsize", "1GiB"),
+					resource.TestCheckResourceAttr("truenas_pool_zvol.test", "volblocksize", "16K"),
+					resource.TestCheckResourceAttr("truenas_pool_zvol.test", "compression", "LZ4"),
+					resource.TestCheckResourceAttr("truenas_pool_zvol.test", "device_path", "zvol/"+name),
+					resource.TestCheckResourceAttr("truenas_pool_zvol.test", "user_properties.com.example:backup", "daily"),
+				),
+			},
+			{
+				Config: testAccPoolZvolResourceConfig(name, "2GiB"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_pool_zvol.test", "volsize", "2GiB"),
+				),
+			},
+			{
+				Config:      testAccPoolZvolResourceConfig(name, "1GiB"),
+				ExpectError: regexp.MustCompile(`Zvol Cannot Shrink`),
+			},
+			{
+				ResourceName:            "truenas_pool_zvol.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"create_ancestors", "deletion_protection", "volsize"},
+			},
+		},
+	})
+}
+
+func testAccPoolZvolResourceConfig(name, volsize string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_pool_zvol" "test" {
+  name                = %q
+  volsize             = %q
+  volblocksize        = "16K"
+  sparse              = true
+  compression         = "LZ4"
+  deletion_protection = false
+
+  user_properties = {
+    "com.example:backup" = "daily"
+  }
+}
+`, name, volsize)
+}
+
+func TestPopulateZvolStateSparse(t *testing.T) {
+	for _, tc := range []struct {
+		refreservation zfsProperty
+		prior          types.Bool
+		want           bool
+	}{
+		{refreservation: zfsProperty{Value: "2.03G", Parsed: json.RawMessage(`2181038080`), Source: "LOCAL"}, prior: types.BoolNull(), want: false},
+		{refreservation: zfsProperty{Value: "0", Parsed: json.RawMessage(`0`), Source: "DEFAULT"}, prior: types.BoolNull(), want: true},
+		{refreservation: zfsProperty{Value: "none", Parsed: json.RawMessage(`null`), Source: "DEFAULT"}, prior: types.BoolValue(false), want: true},
+		{prior: types.BoolValue(true), want: true},
+		{prior: types.BoolNull(), want: false},
+	} {
+		model := poolZvolResourceModel{Sparse: tc.prior}
+		populateZvolState(&model, &poolZvolResult{Name: "tank/vol", Refreservation: tc.refreservation}, "")
+		if model.Sparse.ValueBool() != tc.want {
+			t.Errorf("refreservation %q, prior %s: sparse = %s, want %t", tc.refreservation.Value, tc.prior, model.Sparse, tc.want)
+		}
+	}
+}
@@ -2,41 +2,27 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
-)
-
-var (
-	_ resource.Resource                = (*privilegeResource)(nil)
-	_ resource.ResourceWithConfigure   = (*privilegeResource)(nil)
-	_ resource.ResourceWithImportState = (*privilegeResource)(nil)
 )
 
-type privilegeResource struct {
-	client *client.Client
-}
-
 type privilegeResourceModel struct {
-	ID          types.Int64  `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	BuiltinName types.String `tfsdk:"builtin_name"`
-	LocalGroups types.List   `tfsdk:"local_groups"`
-	DSGroups    types.List   `tfsdk:"ds_groups"`
-	Roles       types.List   `tfsdk:"roles"`
-	WebShell    types.Bool   `tfsdk:"web_shell"`
+	ID          types.Int64    `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	BuiltinName types.String   `tfsdk:"builtin_name"`
+	LocalGroups types.List     `tfsdk:"local_groups"`
+	DSGroups    types.List     `tfsdk:"ds_groups"`
+	Roles       types.List     `tfsdk:"roles"`
+	WebShell    types.Bool     `tfsdk:"web_shell"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 type privilegeGroupObject struct {
@@ -55,15 +41,19 @@ type privilegeResult struct {
 }
 
 func NewPrivilegeResource() resource.Resource {
-	return &privilegeResource{}
-}
-
-func (r *privilegeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_privilege"
+	return newCRUDResource(crudSpec[privilegeResourceModel, privilegeResult]{
+		typeName:     "privilege",
+		namespace:    "privilege",
+		kind:         "Privilege",
+		schema:       privilegeSchema,
+		params:       privilegeParams,
+		populate:     populatePrivilegeState,
+		importLookup: lookupPrivilege,
+	})
 }
 
-func (r *privilegeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func privilegeSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		Description: "Manages a TrueNAS privilege (RBAC role assignment).",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -106,191 +96,41 @@ func (r *privilegeResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "Whether members of this privilege can access the web shell.",
 				Required:    true,
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *privilegeResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
-	}
-
-	r.client = c
-}
-
-func (r *privilegeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan privilegeResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
+func privilegeParams(ctx context.Context, plan, state *privilegeResourceModel, diags *diag.Diagnostics) map[string]any {
 	params := map[string]any{
 		"name":      plan.Name.ValueString(),
 		"web_shell": plan.WebShell.ValueBool(),
 	}
 
+	// A list removed from the configuration is cleared on update.
 	if !plan.LocalGroups.IsNull() && !plan.LocalGroups.IsUnknown() {
 		var ids []int64
-		resp.Diagnostics.Append(plan.LocalGroups.ElementsAs(ctx, &ids, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+		diags.Append(plan.LocalGroups.ElementsAs(ctx, &ids, false)...)
 		params["local_groups"] = ids
+	} else if plan.LocalGroups.IsNull() && state != nil {
+		params["local_groups"] = []int64{}
 	}
 	if !plan.DSGroups.IsNull() && !plan.DSGroups.IsUnknown() {
 		var ids []int64
-		resp.Diagnostics.Append(plan.DSGroups.ElementsAs(ctx, &ids, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+		diags.Append(plan.DSGroups.ElementsAs(ctx, &ids, false)...)
 		params["ds_groups"] = ids
+	} else if plan.DSGroups.IsNull() && state != nil {
+		params["ds_groups"] = []int64{}
 	}
 	if !plan.Roles.IsNull() && !plan.Roles.IsUnknown() {
 		var roles []string
-		resp.Diagnostics.Append(plan.Roles.ElementsAs(ctx, &roles, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+		diags.Append(plan.Roles.ElementsAs(ctx, &roles, false)...)
 		params["roles"] = roles
+	} else if plan.Roles.IsNull() && state != nil {
+		params["roles"] = []string{}
 	}
 
-	var result privilegeResult
-	err := r.client.Call(ctx, "privilege.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Privilege", err.Error())
-		return
-	}
-
-	populatePrivilegeState(ctx, &plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *privilegeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state privilegeResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var result privilegeResult
-	err := r.client.Call(ctx, "privilege.get_instance", []any{state.ID.ValueInt64()}, &result)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
-			resp.State.RemoveResource(ctx)
-			return
-		}
-		resp.Diagnostics.AddError("Error Reading Privilege", err.Error())
-		return
-	}
-
-	populatePrivilegeState(ctx, &state, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-}
-
-func (r *privilegeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan privilegeResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var state privilegeResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := map[string]any{
-		"name":      plan.Name.ValueString(),
-		"web_shell": plan.WebShell.ValueBool(),
-	}
-
-	if !plan.LocalGroups.IsUnknown() {
-		var ids []int64
-		if plan.LocalGroups.IsNull() {
-			ids = []int64{}
-		} else {
-			resp.Diagnostics.Append(plan.LocalGroups.ElementsAs(ctx, &ids, false)...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-		}
-		params["local_groups"] = ids
-	}
-	if !plan.DSGroups.IsUnknown() {
-		var ids []int64
-		if plan.DSGroups.IsNull() {
-			ids = []int64{}
-		} else {
-			resp.Diagnostics.Append(plan.DSGroups.ElementsAs(ctx, &ids, false)...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-		}
-		params["ds_groups"] = ids
-	}
-	if !plan.Roles.IsUnknown() {
-		var roles []string
-		if plan.Roles.IsNull() {
-			roles = []string{}
-		} else {
-			resp.Diagnostics.Append(plan.Roles.ElementsAs(ctx, &roles, false)...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-		}
-		params["roles"] = roles
-	}
-
-	var result privilegeResult
-	err := r.client.Call(ctx, "privilege.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating Privilege", err.Error())
-		return
-	}
-
-	populatePrivilegeState(ctx, &plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *privilegeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state privilegeResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	err := r.client.Call(ctx, "privilege.delete", []any{state.ID.ValueInt64()}, nil)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
-			return
-		}
-		resp.Diagnostics.AddError("Error Deleting Privilege", err.Error())
-		return
-	}
-}
-
-func (r *privilegeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing Privilege",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	return params
 }
 
 func populatePrivilegeState(ctx context.Context, model *privilegeResourceModel, result *privilegeResult, diags *diag.Diagnostics) {
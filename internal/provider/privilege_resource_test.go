@@ -25,6 +25,12 @@ func TestAccPrivilegeResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "truenas_privilege.test",
+				ImportState:       true,
+				ImportStateId:     "tf-acc-test-priv",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultProfileName is the profile used when neither the provider configuration nor
+// TRUENAS_PROFILE selects one.
+const defaultProfileName = "default"
+
+// profile holds the connection settings of one named section of the config file.
+// Empty fields and nil booleans mean the setting is not defined by the profile.
+type profile struct {
+	Host         string
+	APIKeySource apiKeySource
+	Insecure     *bool
+	CAFile       string
+	ReadOnly     *bool
+	DefaultPool  string
+}
+
+// configFilePath returns the path of the profiles file: TRUENAS_CONFIG if set,
+// otherwise $XDG_CONFIG_HOME/truenas/config, falling back to ~/.config/truenas/config.
+func configFilePath() (string, error) {
+	if path := os.Getenv("TRUENAS_CONFIG"); path != "" {
+		return path, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "truenas", "config"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "truenas", "config"), nil
+}
+
+// loadProfile reads the named profile from the config file. When required is false, a
+// missing file or profile is not an error and a nil profile is returned; this is the
+// case when the profile was not explicitly selected.
+func loadProfile(name string, required bool) (*profile, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		// An explicitly configured file must exist, even for the default profile.
+		if os.IsNotExist(err) && !required && os.Getenv("TRUENAS_CONFIG") == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read config file: %w", err)
+	}
+	defer f.Close()
+
+	profiles, err := parseProfiles(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	p, ok := profiles[name]
+	if !ok {
+		if !required {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("profile %q not found in config file %s", name, path)
+	}
+	return p, nil
+}
+
+// parseProfiles parses an INI-style config file:
+//
+//	[default]
+//	host    = wss://nas1.example.com
+//	api_key = 1-abcdef
+//
+//	[backup]
+//	host               = nas2.example.com
+//	credential_process = "pass show truenas/nas2 | jq -R '{api_key: .}'"
+//	insecure           = true
+//	ca_file            = /etc/ssl/certs/nas2-ca.pem
+//	default_pool       = backup
+//
+// Blank lines and lines starting with '#' or ';' are ignored, and values may be
+// wrapped in double quotes.
+func parseProfiles(r io.Reader) (map[string]*profile, error) {
+	profiles := map[string]*profile{}
+	var current *profile
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", lineNo)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", lineNo)
+			}
+			if _, exists := profiles[name]; exists {
+				return nil, fmt.Errorf("line %d: duplicate profile %q", lineNo, name)
+			}
+			current = &profile{}
+			profiles[name] = current
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a [profile] section", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+			value = unquoted
+		}
+
+		switch key {
+		case "host":
+			current.Host = value
+		case "api_key":
+			current.APIKeySource.APIKey = value
+		case "api_key_file":
+			current.APIKeySource.APIKeyFile = value
+		case "credential_process":
+			current.APIKeySource.CredentialProcess = value
+		case "insecure", "read_only":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s must be true or false, got %q", lineNo, key, value)
+			}
+			if key == "insecure" {
+				current.Insecure = &b
+			} else {
+				current.ReadOnly = &b
+			}
+		case "ca_file":
+			current.CAFile = value
+		case "default_pool":
+			current.DefaultPool = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown setting %q", lineNo, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
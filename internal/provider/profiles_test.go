@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testProfilesFile = `
+# Appliances managed by Terraform
+[default]
+host    = wss://nas1.example.com
+api_key = 1-default
+
+[backup]
+host     = nas2.example.com
+api_key  = "2-backup"
+insecure = true
+; pinned CA for the backup box
+ca_file  = /etc/ssl/nas2-ca.pem
+
+[vault]
+host               = nas3.example.com
+credential_process = "vault-truenas --appliance nas3"
+read_only          = true
+default_pool       = vault
+`
+
+func TestParseProfiles(t *testing.T) {
+	profiles, err := parseProfiles(strings.NewReader(testProfilesFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(profiles) != 3 {
+		t.Fatalf("got %d profiles, want 3", len(profiles))
+	}
+
+	def := profiles["default"]
+	if def.Host != "wss://nas1.example.com" || def.APIKeySource.APIKey != "1-default" || def.Insecure != nil || def.CAFile != "" {
+		t.Errorf("unexpected default profile: %+v", def)
+	}
+
+	backup := profiles["backup"]
+	if backup.Host != "nas2.example.com" || backup.APIKeySource.APIKey != "2-backup" || backup.CAFile != "/etc/ssl/nas2-ca.pem" {
+		t.Errorf("unexpected backup profile: %+v", backup)
+	}
+	if backup.Insecure == nil || !*backup.Insecure {
+		t.Errorf("backup profile insecure = %v, want true", backup.Insecure)
+	}
+
+	vault := profiles["vault"]
+	if vault.APIKeySource != (apiKeySource{CredentialProcess: "vault-truenas --appliance nas3"}) || vault.ReadOnly == nil || !*vault.ReadOnly || vault.DefaultPool != "vault" {
+		t.Errorf("unexpected vault profile: %+v", vault)
+	}
+}
+
+func TestParseProfilesErrors(t *testing.T) {
+	tests := map[string]string{
+		"setting outside section": "host = nas\n",
+		"unterminated section":    "[default\n",
+		"empty section":           "[ ]\n",
+		"duplicate section":       "[a]\n[a]\n",
+		"missing equals":          "[a]\nhost\n",
+		"unknown setting":         "[a]\npassword = x\n",
+		"invalid bool":            "[a]\ninsecure = maybe\n",
+		"invalid read_only":       "[a]\nread_only = yes please\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseProfiles(strings.NewReader(input)); err == nil {
+				t.Errorf("expected error for %q", input)
+			}
+		})
+	}
+}
+
+func TestResolveConnectionSettings(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configFile, []byte(testProfilesFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	nullConfig := truenasProviderModel{
+		Host:              types.StringNull(),
+		APIKey:            types.StringNull(),
+		APIKeyFile:        types.StringNull(),
+		CredentialProcess: types.StringNull(),
+		Insecure:          types.BoolNull(),
+		CAFile:            types.StringNull(),
+		Profile:           types.StringNull(),
+		ReadOnly:          types.BoolNull(),
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		config  func(m *truenasProviderModel)
+		want    connectionSettings
+		wantErr bool
+	}{
+		{
+			name: "default profile",
+			want: connectionSettings{Host: "wss://nas1.example.com", APIKeySource: apiKeySource{APIKey: "1-default"}},
+		},
+		{
+			name: "profile from environment",
+			env:  map[string]string{"TRUENAS_PROFILE": "backup"},
+			want: connectionSettings{Host: "nas2.example.com", APIKeySource: apiKeySource{APIKey: "2-backup"}, Insecure: true, CAFile: "/etc/ssl/nas2-ca.pem"},
+		},
+		{
+			name:   "profile attribute overrides environment",
+			env:    map[string]string{"TRUENAS_PROFILE": "missing"},
+			config: func(m *truenasProviderModel) { m.Profile = types.StringValue("backup") },
+			want:   connectionSettings{Host: "nas2.example.com", APIKeySource: apiKeySource{APIKey: "2-backup"}, Insecure: true, CAFile: "/etc/ssl/nas2-ca.pem"},
+		},
+		{
+			name: "environment overrides profile",
+			env:  map[string]string{"TRUENAS_PROFILE": "backup", "TRUENAS_HOST": "nas3.example.com"},
+			want: connectionSettings{Host: "nas3.example.com", APIKeySource: apiKeySource{APIKey: "2-backup"}, Insecure: true, CAFile: "/etc/ssl/nas2-ca.pem"},
+		},
+		{
+			name: "provider block overrides environment and profile",
+			env:  map[string]string{"TRUENAS_PROFILE": "backup", "TRUENAS_API_KEY": "3-env"},
+			config: func(m *truenasProviderModel) {
+				m.APIKey = types.StringValue("4-hcl")
+				m.Insecure = types.BoolValue(false)
+			},
+			want: connectionSettings{Host: "nas2.example.com", APIKeySource: apiKeySource{APIKey: "4-hcl"}, CAFile: "/etc/ssl/nas2-ca.pem"},
+		},
+		{
+			name: "key file in environment replaces profile key",
+			env:  map[string]string{"TRUENAS_API_KEY_FILE": "/run/secrets/truenas"},
+			want: connectionSettings{Host: "wss://nas1.example.com", APIKeySource: apiKeySource{APIKeyFile: "/run/secrets/truenas"}},
+		},
+		{
+			name:   "credential process in provider block replaces environment key",
+			env:    map[string]string{"TRUENAS_API_KEY": "3-env"},
+			config: func(m *truenasProviderModel) { m.CredentialProcess = types.StringValue("get-key") },
+			want:   connectionSettings{Host: "wss://nas1.example.com", APIKeySource: apiKeySource{CredentialProcess: "get-key"}},
+		},
+		{
+			name:    "conflicting environment key sources",
+			env:     map[string]string{"TRUENAS_API_KEY": "3-env", "TRUENAS_CREDENTIAL_PROCESS": "get-key"},
+			wantErr: true,
+		},
+		{
+			name: "read-only profile",
+			env:  map[string]string{"TRUENAS_PROFILE": "vault"},
+			want: connectionSettings{Host: "nas3.example.com", APIKeySource: apiKeySource{CredentialProcess: "vault-truenas --appliance nas3"}, ReadOnly: true, DefaultPool: "vault"},
+		},
+		{
+			name:   "default_pool attribute overrides environment and profile",
+			env:    map[string]string{"TRUENAS_PROFILE": "vault", "TRUENAS_DEFAULT_POOL": "tank"},
+			config: func(m *truenasProviderModel) { m.DefaultPool = types.StringValue("fast") },
+			want:   connectionSettings{Host: "nas3.example.com", APIKeySource: apiKeySource{CredentialProcess: "vault-truenas --appliance nas3"}, ReadOnly: true, DefaultPool: "fast"},
+		},
+		{
+			name: "default pool from environment",
+			env:  map[string]string{"TRUENAS_DEFAULT_POOL": "tank"},
+			want: connectionSettings{Host: "wss://nas1.example.com", APIKeySource: apiKeySource{APIKey: "1-default"}, DefaultPool: "tank"},
+		},
+		{
+			name: "read-only from environment",
+			env:  map[string]string{"TRUENAS_READ_ONLY": "true"},
+			want: connectionSettings{Host: "wss://nas1.example.com", APIKeySource: apiKeySource{APIKey: "1-default"}, ReadOnly: true},
+		},
+		{
+			name:   "read_only attribute overrides environment",
+			env:    map[string]string{"TRUENAS_READ_ONLY": "1"},
+			config: func(m *truenasProviderModel) { m.ReadOnly = types.BoolValue(false) },
+			want:   connectionSettings{Host: "wss://nas1.example.com", APIKeySource: apiKeySource{APIKey: "1-default"}},
+		},
+		{
+			name:    "invalid read-only environment value",
+			env:     map[string]string{"TRUENAS_READ_ONLY": "sometimes"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown profile",
+			env:     map[string]string{"TRUENAS_PROFILE": "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "missing config file",
+			env:     map[string]string{"TRUENAS_CONFIG": filepath.Join(t.TempDir(), "nope")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUENAS_CONFIG", configFile)
+			t.Setenv("TRUENAS_PROFILE", "")
+			t.Setenv("TRUENAS_HOST", "")
+			t.Setenv("TRUENAS_API_KEY", "")
+			t.Setenv("TRUENAS_API_KEY_FILE", "")
+			t.Setenv("TRUENAS_CREDENTIAL_PROCESS", "")
+			t.Setenv("TRUENAS_READ_ONLY", "")
+			t.Setenv("TRUENAS_DEFAULT_POOL", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			config := nullConfig
+			if tt.config != nil {
+				tt.config(&config)
+			}
+
+			got, err := resolveConnectionSettings(config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveConnectionSettingsWithoutConfigFile(t *testing.T) {
+	t.Setenv("TRUENAS_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TRUENAS_PROFILE", "")
+	t.Setenv("TRUENAS_HOST", "nas.example.com")
+	t.Setenv("TRUENAS_API_KEY", "1-env")
+	t.Setenv("TRUENAS_API_KEY_FILE", "")
+	t.Setenv("TRUENAS_CREDENTIAL_PROCESS", "")
+	t.Setenv("TRUENAS_READ_ONLY", "")
+
+	got, err := resolveConnectionSettings(truenasProviderModel{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := connectionSettings{Host: "nas.example.com", APIKeySource: apiKeySource{APIKey: "1-env"}}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
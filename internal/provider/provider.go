@@ -2,31 +2,67 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/barodeur/terraform-provider-truenas/internal/client"
 )
 
-var _ provider.Provider = (*truenasProvider)(nil)
+var (
+	_ provider.Provider                       = (*truenasProvider)(nil)
+	_ provider.ProviderWithFunctions          = (*truenasProvider)(nil)
+	_ provider.ProviderWithEphemeralResources = (*truenasProvider)(nil)
+	_ provider.ProviderWithConfigValidators   = (*truenasProvider)(nil)
+)
 
 type truenasProvider struct {
 	cachedClient *client.Client
 	cachedHost   string
 	cachedAPIKey string
 	cachedInsec  bool
+	cachedCAFile string
+	cachedRO     bool
+}
+
+// providerData is passed to resources, data sources and ephemeral resources by
+// Configure: the client and the provider settings that only the resources use.
+type providerData struct {
+	client *client.Client
+
+	// ownershipTag identifies the Terraform workspace; resources write it on the
+	// objects they manage.
+	ownershipTag string
+
+	// defaultPool is the pool relative dataset names and paths are resolved against.
+	defaultPool string
 }
 
 type truenasProviderModel struct {
-	Host     types.String `tfsdk:"host"`
-	APIKey   types.String `tfsdk:"api_key"`
-	Insecure types.Bool   `tfsdk:"insecure"`
+	Host              types.String `tfsdk:"host"`
+	APIKey            types.String `tfsdk:"api_key"`
+	APIKeyFile        types.String `tfsdk:"api_key_file"`
+	CredentialProcess types.String `tfsdk:"credential_process"`
+	Insecure          types.Bool   `tfsdk:"insecure"`
+	CAFile            types.String `tfsdk:"ca_file"`
+	Profile           types.String `tfsdk:"profile"`
+	ReadOnly          types.Bool   `tfsdk:"read_only"`
+	OwnershipTag      types.String `tfsdk:"ownership_tag"`
+	DefaultPool       types.String `tfsdk:"default_pool"`
 }
 
 func New() func() provider.Provider {
@@ -44,22 +80,72 @@ func (p *truenasProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 		Description: "Interact with TrueNAS Scale via its WebSocket API.",
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Description: "The WebSocket URL of the TrueNAS server (e.g. wss://truenas.local). If no scheme is provided, wss:// is assumed. Can also be set with the TRUENAS_HOST environment variable.",
-				Required:    true,
+				Description: "The WebSocket URL of the TrueNAS server (e.g. wss://truenas.local). If no scheme is provided, wss:// is assumed. Can also be set with the TRUENAS_HOST environment variable or in a config file profile.",
+				Optional:    true,
 			},
 			"api_key": schema.StringAttribute{
-				Description: "The API key for authenticating with TrueNAS. Can also be set with the TRUENAS_API_KEY environment variable.",
-				Required:    true,
+				Description: "The API key for authenticating with TrueNAS. Can also be set with the TRUENAS_API_KEY environment variable or in a config file profile.",
+				Optional:    true,
 				Sensitive:   true,
 			},
+			"api_key_file": schema.StringAttribute{
+				Description: "Path to a file containing the API key, re-read every time the provider is configured. Conflicts with api_key and credential_process. Can also be set with the TRUENAS_API_KEY_FILE environment variable or in a config file profile.",
+				Optional:    true,
+			},
+			"credential_process": schema.StringAttribute{
+				Description: "A command, run through the system shell, that prints the API key as JSON (e.g. {\"version\": 1, \"api_key\": \"...\"}) on standard output. " +
+					"Conflicts with api_key and api_key_file. Can also be set with the TRUENAS_CREDENTIAL_PROCESS environment variable or in a config file profile.",
+				Optional: true,
+			},
 			"insecure": schema.BoolAttribute{
-				Description: "Skip TLS certificate verification. Defaults to false.",
+				Description: "Skip TLS certificate verification. Can also be set in a config file profile. Defaults to false.",
 				Optional:    true,
 			},
+			"ca_file": schema.StringAttribute{
+				Description: "Path to a PEM file with the CA certificates used to verify the server's TLS certificate, instead of the system roots. Can also be set in a config file profile.",
+				Optional:    true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "The name of the profile to load from the config file (~/.config/truenas/config, or the path in TRUENAS_CONFIG). " +
+					"Settings in the provider block and environment variables take precedence over the profile. " +
+					"Can also be set with the TRUENAS_PROFILE environment variable. Defaults to \"default\", which is only used if present.",
+				Optional: true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Reject every API call that may modify the system, e.g. for drift-detection plans. " +
+					"Only query and configuration reads are allowed; applying a change fails with an error naming the blocked method. " +
+					"Can also be set with the TRUENAS_READ_ONLY environment variable or in a config file profile. Defaults to false.",
+				Optional: true,
+			},
+			"default_pool": schema.StringAttribute{
+				Description: "The pool that relative dataset names and paths, written with a leading \"./\", are resolved against: " +
+					"\"./apps\" is the dataset tank/apps, the share path /mnt/tank/apps or the zvol path zvol/tank/apps when default_pool is \"tank\". " +
+					"Can also be set with the TRUENAS_DEFAULT_POOL environment variable or in a config file profile.",
+				Optional: true,
+			},
+			"ownership_tag": schema.StringAttribute{
+				Description: "A tag identifying this Terraform workspace, written on the objects the provider manages: as the " + ownershipProperty + " ZFS user property on datasets and zvols, " +
+					"and at the end of the comment of NFS and SMB shares and iSCSI extents and of the description of cron jobs. " +
+					"Users, iSCSI targets and NVMe-oF objects have no free-text field to hold it and are not tagged. " +
+					"Importing an object tagged by another workspace produces a warning. Unsetting the tag leaves existing tags in place; " +
+					"remove them by hand (zfs inherit " + ownershipProperty + ", or by editing the comment) if needed. " +
+					"Letters, digits and . _ - : / @ only. Can also be set with the TRUENAS_OWNERSHIP_TAG environment variable.",
+				Optional: true,
+			},
 		},
 	}
 }
 
+func (p *truenasProvider) ConfigValidators(_ context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(
+			path.MatchRoot("api_key"),
+			path.MatchRoot("api_key_file"),
+			path.MatchRoot("credential_process"),
+		),
+	}
+}
+
 func (p *truenasProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var config truenasProviderModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
@@ -67,28 +153,50 @@ func (p *truenasProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	host := os.Getenv("TRUENAS_HOST")
-	apiKey := os.Getenv("TRUENAS_API_KEY")
+	settings, err := resolveConnectionSettings(config)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unable to Load TrueNAS Profile",
+			err.Error(),
+		)
+		return
+	}
+	host, insecure, caFile, readOnly := settings.Host, settings.Insecure, settings.CAFile, settings.ReadOnly
+	defaultPool := settings.DefaultPool
+
+	if err := validateDefaultPool(defaultPool); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("default_pool"), "Invalid Default Pool", err.Error())
+		return
+	}
 
-	if !config.Host.IsNull() {
-		host = config.Host.ValueString()
+	ownershipTag := os.Getenv("TRUENAS_OWNERSHIP_TAG")
+	if !config.OwnershipTag.IsNull() {
+		ownershipTag = config.OwnershipTag.ValueString()
 	}
-	if !config.APIKey.IsNull() {
-		apiKey = config.APIKey.ValueString()
+	if err := validateOwnershipTag(ownershipTag); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ownership_tag"), "Invalid Ownership Tag", err.Error())
+		return
+	}
+
+	apiKey, err := settings.APIKeySource.load(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Load TrueNAS API Key", err.Error())
+		return
 	}
 
 	if host == "" {
 		resp.Diagnostics.AddError(
 			"Missing Host Configuration",
 			"The provider cannot create the TrueNAS client because the host is not configured. "+
-				"Set it in the provider configuration block or the TRUENAS_HOST environment variable.",
+				"Set it in the provider configuration block, the TRUENAS_HOST environment variable, or a config file profile.",
 		)
 	}
 	if apiKey == "" {
 		resp.Diagnostics.AddError(
 			"Missing API Key Configuration",
 			"The provider cannot create the TrueNAS client because the API key is not configured. "+
-				"Set it in the provider configuration block or the TRUENAS_API_KEY environment variable.",
+				"Set api_key, api_key_file or credential_process in the provider configuration block, the matching TRUENAS_* environment variable, or a config file profile.",
 		)
 	}
 	if resp.Diagnostics.HasError() {
@@ -100,22 +208,32 @@ func (p *truenasProvider) Configure(ctx context.Context, req provider.ConfigureR
 		host = "wss://" + host
 	}
 
-	insecure := false
-	if !config.Insecure.IsNull() {
-		insecure = config.Insecure.ValueBool()
-	}
-
-	// Reuse existing client if config hasn't changed
+	// Reuse existing client if config hasn't changed. The API key is compared after
+	// loading, so a key rotated in api_key_file or credential_process reconnects.
 	if p.cachedClient != nil &&
 		p.cachedHost == host &&
 		p.cachedAPIKey == apiKey &&
-		p.cachedInsec == insecure {
-		resp.DataSourceData = p.cachedClient
-		resp.ResourceData = p.cachedClient
+		p.cachedInsec == insecure &&
+		p.cachedCAFile == caFile &&
+		p.cachedRO == readOnly {
+		p.setProviderData(resp, ownershipTag, defaultPool)
+		return
+	}
+
+	tlsConfig, err := newTLSConfig(insecure, caFile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_file"),
+			"Invalid TLS Configuration",
+			err.Error(),
+		)
 		return
 	}
 
-	c, err := client.NewClient(ctx, host, apiKey, insecure)
+	c, err := client.NewClientWithOptions(ctx, host, apiKey, client.Options{
+		TLSConfig: tlsConfig,
+		ReadOnly:  readOnly,
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create TrueNAS Client",
@@ -124,20 +242,154 @@ func (p *truenasProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	if p.cachedClient != nil {
+		if err := p.cachedClient.Close(); err != nil {
+			tflog.Debug(ctx, "Error closing previous TrueNAS client", map[string]any{"error": err.Error()})
+		}
+	}
+
 	p.cachedClient = c
 	p.cachedHost = host
 	p.cachedAPIKey = apiKey
 	p.cachedInsec = insecure
+	p.cachedCAFile = caFile
+	p.cachedRO = readOnly
 
-	resp.DataSourceData = c
-	resp.ResourceData = c
+	p.setProviderData(resp, ownershipTag, defaultPool)
+}
+
+// setProviderData hands the cached client and the resource settings to the resources,
+// data sources and ephemeral resources.
+func (p *truenasProvider) setProviderData(resp *provider.ConfigureResponse, ownershipTag, defaultPool string) {
+	data := &providerData{
+		client:       p.cachedClient,
+		ownershipTag: ownershipTag,
+		defaultPool:  defaultPool,
+	}
+	resp.DataSourceData = data
+	resp.ResourceData = data
+	resp.EphemeralResourceData = data
+}
+
+// connectionSettings are the resolved settings used to connect to TrueNAS.
+type connectionSettings struct {
+	Host         string
+	APIKeySource apiKeySource
+	Insecure     bool
+	CAFile       string
+	ReadOnly     bool
+	DefaultPool  string
+}
+
+// resolveConnectionSettings merges the provider configuration, environment variables
+// and config file profile. The provider block takes precedence over environment
+// variables, which take precedence over the profile.
+func resolveConnectionSettings(config truenasProviderModel) (connectionSettings, error) {
+	profileName, profileRequired := defaultProfileName, false
+	if v := os.Getenv("TRUENAS_PROFILE"); v != "" {
+		profileName, profileRequired = v, true
+	}
+	if !config.Profile.IsNull() {
+		profileName, profileRequired = config.Profile.ValueString(), true
+	}
+
+	prof, err := loadProfile(profileName, profileRequired)
+	if err != nil {
+		return connectionSettings{}, err
+	}
+	if prof == nil {
+		prof = &profile{}
+	}
+
+	settings := connectionSettings{
+		Host:        prof.Host,
+		Insecure:    prof.Insecure != nil && *prof.Insecure,
+		CAFile:      prof.CAFile,
+		ReadOnly:    prof.ReadOnly != nil && *prof.ReadOnly,
+		DefaultPool: prof.DefaultPool,
+	}
+	if err := settings.APIKeySource.override(fmt.Sprintf("profile %q", profileName), prof.APIKeySource); err != nil {
+		return connectionSettings{}, err
+	}
+
+	if v := os.Getenv("TRUENAS_HOST"); v != "" {
+		settings.Host = v
+	}
+	if v := os.Getenv("TRUENAS_READ_ONLY"); v != "" {
+		readOnly, err := strconv.ParseBool(v)
+		if err != nil {
+			return connectionSettings{}, fmt.Errorf("TRUENAS_READ_ONLY must be true or false, got %q", v)
+		}
+		settings.ReadOnly = readOnly
+	}
+	if v := os.Getenv("TRUENAS_DEFAULT_POOL"); v != "" {
+		settings.DefaultPool = v
+	}
+	if err := settings.APIKeySource.override("the environment", apiKeySource{
+		APIKey:            os.Getenv("TRUENAS_API_KEY"),
+		APIKeyFile:        os.Getenv("TRUENAS_API_KEY_FILE"),
+		CredentialProcess: os.Getenv("TRUENAS_CREDENTIAL_PROCESS"),
+	}); err != nil {
+		return connectionSettings{}, err
+	}
+
+	if !config.Host.IsNull() {
+		settings.Host = config.Host.ValueString()
+	}
+	if err := settings.APIKeySource.override("the provider configuration", apiKeySource{
+		APIKey:            config.APIKey.ValueString(),
+		APIKeyFile:        config.APIKeyFile.ValueString(),
+		CredentialProcess: config.CredentialProcess.ValueString(),
+	}); err != nil {
+		return connectionSettings{}, err
+	}
+	if !config.Insecure.IsNull() {
+		settings.Insecure = config.Insecure.ValueBool()
+	}
+	if !config.CAFile.IsNull() {
+		settings.CAFile = config.CAFile.ValueString()
+	}
+	if !config.ReadOnly.IsNull() {
+		settings.ReadOnly = config.ReadOnly.ValueBool()
+	}
+	if !config.DefaultPool.IsNull() {
+		settings.DefaultPool = config.DefaultPool.ValueString()
+	}
+
+	return settings, nil
+}
+
+// newTLSConfig returns the TLS configuration for the WebSocket connection, or nil to use
+// the defaults.
+func newTLSConfig(insecure bool, caFile string) (*tls.Config, error) {
+	if !insecure && caFile == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
 }
 
 func (p *truenasProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewAPIKeyResource,
 		NewCronjobResource,
+		NewPoolResource,
+		NewPoolImportResource,
 		NewPoolDatasetResource,
+		NewPoolZvolResource,
+		NewPoolSnapshotResource,
 		NewGroupResource,
 		NewPrivilegeResource,
 		NewUserResource,
@@ -172,3 +424,20 @@ func (p *truenasProvider) DataSources(_ context.Context) []func() datasource.Dat
 		NewNVMeTGlobalDataSource,
 	}
 }
+
+func (p *truenasProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewPoolDatasetKeyEphemeralResource,
+	}
+}
+
+func (p *truenasProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewParseSizeFunction,
+		NewFormatSizeFunction,
+		NewIQNFunction,
+		NewNQNFunction,
+		NewValidateCronFunction,
+		NewDatasetParentFunction,
+	}
+}
@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"fmt"
+	"maps"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
@@ -32,3 +39,70 @@ provider "truenas" {
 }
 `
 }
+
+func TestAccProvider_readOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "truenas" {
+  host      = "` + os.Getenv("TRUENAS_HOST") + `"
+  api_key   = "` + os.Getenv("TRUENAS_API_KEY") + `"
+  insecure  = true
+  read_only = true
+}
+
+resource "truenas_group" "test" {
+  name = "tf-acc-test-readonly"
+}
+`,
+				ExpectError: regexp.MustCompile(`refusing to call group.create`),
+			},
+		},
+	})
+}
+
+// functionTestCase is a call to a provider function and its expected outcome.
+type functionTestCase struct {
+	expr    string // the function call, evaluated as an output value
+	want    string // the expected output value
+	wantErr string // a regular expression matching the error, if the call must fail
+}
+
+// testFunction evaluates provider function calls in Terraform outputs: the calls that
+// succeed together in a first step, then each failing call in its own step. Terraform
+// converts the arguments and surfaces the errors as it would in a configuration.
+// Provider-defined functions require Terraform 1.8 or later.
+func testFunction(t *testing.T, cases map[string]functionTestCase) {
+	t.Helper()
+
+	var config strings.Builder
+	var checks []resource.TestCheckFunc
+	var errorSteps []resource.TestStep
+	for _, name := range slices.Sorted(maps.Keys(cases)) {
+		tc := cases[name]
+		output := fmt.Sprintf("output %q {\n  value = %s\n}\n", strings.ReplaceAll(name, " ", "_"), tc.expr)
+		if tc.wantErr != "" {
+			errorSteps = append(errorSteps, resource.TestStep{
+				Config:      output,
+				ExpectError: regexp.MustCompile(tc.wantErr),
+			})
+			continue
+		}
+		config.WriteString(output)
+		checks = append(checks, resource.TestCheckOutput(strings.ReplaceAll(name, " ", "_"), tc.want))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{{
+			Config: config.String(),
+			Check:  resource.ComposeAggregateTestCheckFunc(checks...),
+		}}, errorSteps...),
+	})
+}
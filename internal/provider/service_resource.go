@@ -2,10 +2,11 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -30,12 +31,13 @@ type serviceResource struct {
 }
 
 type serviceResourceModel struct {
-	ID      types.Int64  `tfsdk:"id"`
-	Service types.String `tfsdk:"service"`
-	Enable  types.Bool   `tfsdk:"enable"`
-	Running types.Bool   `tfsdk:"running"`
-	State   types.String `tfsdk:"state"`
-	Pids    types.List   `tfsdk:"pids"`
+	ID       types.Int64    `tfsdk:"id"`
+	Service  types.String   `tfsdk:"service"`
+	Enable   types.Bool     `tfsdk:"enable"`
+	Running  types.Bool     `tfsdk:"running"`
+	State    types.String   `tfsdk:"state"`
+	Pids     types.List     `tfsdk:"pids"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type serviceResult struct {
@@ -54,11 +56,12 @@ func (r *serviceResource) Metadata(_ context.Context, req resource.MetadataReque
 	resp.TypeName = req.ProviderTypeName + "_service"
 }
 
-func (r *serviceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *serviceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages a TrueNAS service. Services are pre-existing system entities that can be enabled/disabled and started/stopped.\n\n" +
 			"~> **Note:** Services are pre-existing system entities in TrueNAS. " +
-			"Destroying this resource only removes it from Terraform state — it does not delete the service itself.",
+			"Destroying this resource only removes it from Terraform state — it does not delete the service itself.\n\n" +
+			"Creating or updating the resource, including waiting for the service to start or stop, times out after 30 seconds unless overridden with `timeouts`.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Description: "The unique identifier of the service.",
@@ -95,6 +98,7 @@ func (r *serviceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Computed:    true,
 				ElementType: types.Int64Type,
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true}),
 		},
 	}
 }
@@ -104,16 +108,16 @@ func (r *serviceResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
 }
 
 func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -123,6 +127,15 @@ func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultServiceTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Check config to determine if user explicitly set running
 	var configRunning types.Bool
 	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("running"), &configRunning)...)
@@ -193,7 +206,7 @@ func (r *serviceResource) Read(ctx context.Context, req resource.ReadRequest, re
 	var result serviceResult
 	err := r.client.Call(ctx, "service.get_instance", []any{state.ID.ValueInt64()}, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -212,6 +225,15 @@ func (r *serviceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultServiceTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var state serviceResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -298,7 +320,8 @@ func (r *serviceResource) ImportState(ctx context.Context, req resource.ImportSt
 
 // controlService starts or stops a service and waits for the state to converge.
 // service.control is a @job method that returns asynchronously, so we poll
-// service.query until the service reaches the expected state.
+// service.query until the service reaches the expected state or the context's
+// deadline (set from the resource timeouts) expires.
 func (r *serviceResource) controlService(ctx context.Context, serviceName string, start bool) error {
 	action := "STOP"
 	expectedState := "STOPPED"
@@ -315,15 +338,14 @@ func (r *serviceResource) controlService(ctx context.Context, serviceName string
 	// Poll until the service reaches the expected state or context is cancelled
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
-	timeout := time.NewTimer(30 * time.Second)
-	defer timeout.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out waiting for service %q to reach state %s", serviceName, expectedState)
+			}
 			return ctx.Err()
-		case <-timeout.C:
-			return fmt.Errorf("timed out waiting for service %q to reach state %s", serviceName, expectedState)
 		case <-ticker.C:
 			var results []serviceResult
 			err = r.client.Call(ctx, "service.query", []any{
@@ -58,6 +58,23 @@ func TestAccServiceResource_update(t *testing.T) {
 	})
 }
 
+func TestAccServiceResource_timeouts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceResourceConfigWithTimeouts("ssh", "5m"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("truenas_service.test", "running", "true"),
+					resource.TestCheckResourceAttr("truenas_service.test", "timeouts.create", "5m"),
+					resource.TestCheckResourceAttr("truenas_service.test", "timeouts.update", "5m"),
+				),
+			},
+		},
+	})
+}
+
 func testAccServiceResourceConfig(service string, enable bool, running bool) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_service" "test" {
@@ -67,3 +84,18 @@ resource "truenas_service" "test" {
 }
 `, service, enable, running)
 }
+
+func testAccServiceResourceConfigWithTimeouts(service, timeout string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "truenas_service" "test" {
+  service = %q
+  enable  = true
+  running = true
+
+  timeouts = {
+    create = %q
+    update = %q
+  }
+}
+`, service, timeout, timeout)
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = sizeType{}
+	_ basetypes.StringValuableWithSemanticEquals = sizeValue{}
+	_ xattr.ValidateableAttribute                = sizeValue{}
+	_ validator.String                           = sizeAtLeastValidator{}
+)
+
+// sizeType is a string attribute type holding a size in bytes. Values may be written as
+// plain integers or with a unit suffix ("50GiB", "1.5T"), using the same rules as the
+// parse_size function. Two values are semantically equal when they denote the same
+// number of bytes, so the byte counts returned by the API never produce a diff.
+type sizeType struct {
+	basetypes.StringType
+}
+
+func (t sizeType) String() string {
+	return "sizeType"
+}
+
+func (t sizeType) Equal(o attr.Type) bool {
+	other, ok := o.(sizeType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t sizeType) ValueType(_ context.Context) attr.Value {
+	return sizeValue{}
+}
+
+func (t sizeType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return sizeValue{StringValue: in}, nil
+}
+
+func (t sizeType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return sizeValue{StringValue: stringValue}, nil
+}
+
+// sizeValue is the value type of sizeType.
+type sizeValue struct {
+	basetypes.StringValue
+}
+
+func sizeNull() sizeValue {
+	return sizeValue{StringValue: types.StringNull()}
+}
+
+// sizeBytes returns a size value holding a plain byte count, as read from the API.
+func sizeBytes(n int64) sizeValue {
+	return sizeValue{StringValue: types.StringValue(strconv.FormatInt(n, 10))}
+}
+
+func (v sizeValue) Type(_ context.Context) attr.Type {
+	return sizeType{}
+}
+
+func (v sizeValue) Equal(o attr.Value) bool {
+	other, ok := o.(sizeValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// ValueBytes returns the size in bytes. Values are validated during planning, so an
+// unparsable value (only possible for null or unknown values) yields 0.
+func (v sizeValue) ValueBytes() int64 {
+	n, err := parseSize(v.ValueString())
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func (v sizeValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(sizeValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got %T. Please report this to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	prior, err := parseSize(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	current, err := parseSize(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+
+	return prior == current, diags
+}
+
+func (v sizeValue) ValidateAttribute(_ context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := parseSize(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Size", err.Error())
+	}
+}
+
+// sizeAtLeastValidator checks that a size is at least min bytes, optionally allowing 0
+// (which TrueNAS uses to disable quotas).
+type sizeAtLeastValidator struct {
+	min       int64
+	allowZero bool
+}
+
+func sizeAtLeast(min int64) sizeAtLeastValidator {
+	return sizeAtLeastValidator{min: min}
+}
+
+func sizeZeroOrAtLeast(min int64) sizeAtLeastValidator {
+	return sizeAtLeastValidator{min: min, allowZero: true}
+}
+
+func (v sizeAtLeastValidator) Description(_ context.Context) string {
+	if v.allowZero {
+		return fmt.Sprintf("size must be 0 or at least %s", formatSize(v.min))
+	}
+	return fmt.Sprintf("size must be at least %s", formatSize(v.min))
+}
+
+func (v sizeAtLeastValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sizeAtLeastValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	n, err := parseSize(req.ConfigValue.ValueString())
+	if err != nil {
+		// Reported by sizeValue.ValidateAttribute.
+		return
+	}
+	if n >= v.min || (v.allowZero && n == 0) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+	)
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func sizeValueOf(s string) sizeValue {
+	return sizeValue{StringValue: types.StringValue(s)}
+}
+
+func TestSizeValueSemanticEquals(t *testing.T) {
+	tests := []struct {
+		prior, current string
+		want           bool
+	}{
+		{"50GiB", "53687091200", true},
+		{"1.5T", "1649267441664", true},
+		{"1024", "1K", true},
+		{"1G", "1GB", true},
+		{"50GiB", "50GB", true},
+		{"50GiB", "51GiB", false},
+		{"1T", "1000000000000", false},
+		{"bogus", "bogus", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prior+"="+tt.current, func(t *testing.T) {
+			got, diags := sizeValueOf(tt.prior).StringSemanticEquals(context.Background(), sizeValueOf(tt.current))
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if got != tt.want {
+				t.Errorf("StringSemanticEquals(%q, %q) = %v, want %v", tt.prior, tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSizeValueValidateAttribute(t *testing.T) {
+	tests := []struct {
+		value   sizeValue
+		wantErr bool
+	}{
+		{value: sizeValueOf("50GiB")},
+		{value: sizeValueOf("4096")},
+		{value: sizeNull()},
+		{value: sizeValue{StringValue: types.StringUnknown()}},
+		{value: sizeValueOf("50 parsecs"), wantErr: true},
+		{value: sizeValueOf("-1G"), wantErr: true},
+		{value: sizeValueOf(""), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value.String(), func(t *testing.T) {
+			resp := &xattr.ValidateAttributeResponse{}
+			tt.value.ValidateAttribute(context.Background(), xattr.ValidateAttributeRequest{Path: path.Root("quota")}, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSizeAtLeastValidator(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator sizeAtLeastValidator
+		value     string
+		wantErr   bool
+	}{
+		{name: "above", validator: sizeAtLeast(1 << 30), value: "2G"},
+		{name: "equal", validator: sizeAtLeast(1 << 30), value: "1073741824"},
+		{name: "below", validator: sizeAtLeast(1 << 30), value: "100", wantErr: true},
+		{name: "zero not allowed", validator: sizeAtLeast(1 << 30), value: "0", wantErr: true},
+		{name: "zero allowed", validator: sizeZeroOrAtLeast(1 << 30), value: "0"},
+		{name: "zero with unit", validator: sizeZeroOrAtLeast(1 << 30), value: "0G"},
+		{name: "below with zero allowed", validator: sizeZeroOrAtLeast(1 << 30), value: "512M", wantErr: true},
+		{name: "unparsable is left to the type", validator: sizeAtLeast(1 << 30), value: "huge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("quota"),
+				ConfigValue: types.StringValue(tt.value),
+			}
+			resp := &validator.StringResponse{}
+			tt.validator.ValidateString(context.Background(), req, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -3,9 +3,9 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/barodeur/terraform-provider-truenas/internal/client"
@@ -25,25 +26,42 @@ var (
 	_ resource.Resource                = (*smbShareResource)(nil)
 	_ resource.ResourceWithConfigure   = (*smbShareResource)(nil)
 	_ resource.ResourceWithImportState = (*smbShareResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*smbShareResource)(nil)
 )
 
+// smbSharePurposes are the share presets accepted by sharing.smb.create.
+var smbSharePurposes = []string{
+	"DEFAULT_SHARE",
+	"LEGACY_SHARE",
+	"TIMEMACHINE_SHARE",
+	"MULTIPROTOCOL_SHARE",
+	"TIME_LOCKED_SHARE",
+	"PRIVATE_DATASETS_SHARE",
+	"EXTERNAL_SHARE",
+	"VEEAM_REPOSITORY_SHARE", // 25.10+
+}
+
 type smbShareResource struct {
-	client *client.Client
+	client   *client.Client
+	provider *providerData
 }
 
 type smbShareResourceModel struct {
-	ID                   types.Int64  `tfsdk:"id"`
-	Name                 types.String `tfsdk:"name"`
-	Path                 types.String `tfsdk:"path"`
-	Comment              types.String `tfsdk:"comment"`
-	Enabled              types.Bool   `tfsdk:"enabled"`
-	Purpose              types.String `tfsdk:"purpose"`
-	Readonly             types.Bool   `tfsdk:"readonly"`
-	Browsable            types.Bool   `tfsdk:"browsable"`
-	AccessBasedShareEnum types.Bool   `tfsdk:"access_based_share_enumeration"`
-	Hostsallow           types.List   `tfsdk:"hostsallow"`
-	Hostsdeny            types.List   `tfsdk:"hostsdeny"`
-	Locked               types.Bool   `tfsdk:"locked"`
+	ID                   types.Int64    `tfsdk:"id"`
+	Name                 types.String   `tfsdk:"name"`
+	Path                 types.String   `tfsdk:"path"`
+	ResolvedPath         types.String   `tfsdk:"resolved_path"`
+	Comment              types.String   `tfsdk:"comment"`
+	Enabled              types.Bool     `tfsdk:"enabled"`
+	Purpose              types.String   `tfsdk:"purpose"`
+	Readonly             types.Bool     `tfsdk:"readonly"`
+	Browsable            types.Bool     `tfsdk:"browsable"`
+	AccessBasedShareEnum types.Bool     `tfsdk:"access_based_share_enumeration"`
+	Hostsallow           types.List     `tfsdk:"hostsallow"`
+	Hostsdeny            types.List     `tfsdk:"hostsdeny"`
+	Locked               types.Bool     `tfsdk:"locked"`
+	OwnershipTag         types.String   `tfsdk:"ownership_tag"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
 }
 
 type smbShareResultOptions struct {
@@ -74,7 +92,7 @@ func (r *smbShareResource) Metadata(_ context.Context, req resource.MetadataRequ
 	resp.TypeName = req.ProviderTypeName + "_smb_share"
 }
 
-func (r *smbShareResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *smbShareResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a TrueNAS SMB share.",
 		Attributes: map[string]schema.Attribute{
@@ -90,9 +108,10 @@ func (r *smbShareResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Required:    true,
 			},
 			"path": schema.StringAttribute{
-				Description: "The filesystem path to share (e.g. /mnt/tank/data).",
+				Description: "The filesystem path to share (e.g. /mnt/tank/data), or a path relative to the provider's default_pool (e.g. ./data).",
 				Required:    true,
 			},
+			"resolved_path": resolvedPathAttribute("path", "/mnt/tank/data"),
 			"comment": schema.StringAttribute{
 				Description: "Description of the share.",
 				Optional:    true,
@@ -104,9 +123,12 @@ func (r *smbShareResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Default:     booldefault.StaticBool(true),
 			},
 			"purpose": schema.StringAttribute{
-				Description: "Share preset/purpose (e.g. DEFAULT_SHARE, TIMEMACHINE_SHARE, MULTIPROTOCOL_SHARE).",
+				Description: "Share preset/purpose (e.g. DEFAULT_SHARE, TIMEMACHINE_SHARE, MULTIPROTOCOL_SHARE). VEEAM_REPOSITORY_SHARE requires TrueNAS 25.10 or later.",
 				Optional:    true,
 				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(smbSharePurposes...),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -149,6 +171,8 @@ func (r *smbShareResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Description: "Whether the share is locked (e.g. because the underlying dataset is encrypted and locked).",
 				Computed:    true,
 			},
+			"ownership_tag": ownershipTagAttribute("share", "at the end of its comment, as [terraform:<tag>]"),
+			"timeouts":      timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
@@ -158,16 +182,40 @@ func (r *smbShareResource) Configure(_ context.Context, req resource.ConfigureRe
 		return
 	}
 
-	c, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = c
+	r.client = data.client
+	r.provider = data
+}
+
+func (r *smbShareResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var purpose types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("purpose"), &purpose)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if purpose.ValueString() == "VEEAM_REPOSITORY_SHARE" {
+		requireAttributeCapability(r.client, capSMBVeeamRepository, path.Root("purpose"), &resp.Diagnostics)
+	}
+
+	var p types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("path"), &p)...)
+	checkRelativePath(r.provider, path.Root("path"), p, &resp.Diagnostics)
+	planResolvedPath(ctx, r.provider, mountPath, path.Root("resolved_path"), p, resp)
+
+	planOwnershipTag(ctx, r.provider, req, resp)
 }
 
 func (r *smbShareResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -177,14 +225,23 @@ func (r *smbShareResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	params := map[string]any{
 		"name":    plan.Name.ValueString(),
-		"path":    plan.Path.ValueString(),
+		"path":    resolvePoolPath(r.provider.defaultPool, mountPath, plan.Path.ValueString()),
 		"enabled": plan.Enabled.ValueBool(),
 	}
 
-	if !plan.Comment.IsNull() && !plan.Comment.IsUnknown() {
-		params["comment"] = plan.Comment.ValueString()
+	if comment := withOwnershipMarker(plan.Comment.ValueString(), plan.OwnershipTag.ValueString()); comment != "" {
+		params["comment"] = comment
 	}
 	if !plan.Purpose.IsNull() && !plan.Purpose.IsUnknown() {
 		params["purpose"] = plan.Purpose.ValueString()
@@ -227,7 +284,7 @@ func (r *smbShareResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	populateSMBShareState(ctx, &plan, &result, &resp.Diagnostics)
+	populateSMBShareState(ctx, &plan, &result, r.provider.defaultPool, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -241,7 +298,7 @@ func (r *smbShareResource) Read(ctx context.Context, req resource.ReadRequest, r
 	var result smbShareResult
 	err := r.client.Call(ctx, "sharing.smb.get_instance", []any{state.ID.ValueInt64()}, &result)
 	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+		if isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -249,7 +306,7 @@ func (r *smbShareResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	populateSMBShareState(ctx, &state, &result, &resp.Diagnostics)
+	populateSMBShareState(ctx, &state, &result, r.provider.defaultPool, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -260,6 +317,15 @@ func (r *smbShareResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var state smbShareResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -268,15 +334,11 @@ func (r *smbShareResource) Update(ctx context.Context, req resource.UpdateReques
 
 	params := map[string]any{
 		"name":    plan.Name.ValueString(),
-		"path":    plan.Path.ValueString(),
+		"path":    resolvePoolPath(r.provider.defaultPool, mountPath, plan.Path.ValueString()),
 		"enabled": plan.Enabled.ValueBool(),
 	}
 
-	if !plan.Comment.IsNull() {
-		params["comment"] = plan.Comment.ValueString()
-	} else {
-		params["comment"] = ""
-	}
+	params["comment"] = withOwnershipMarker(plan.Comment.ValueString(), plan.OwnershipTag.ValueString())
 	if !plan.Readonly.IsNull() {
 		params["readonly"] = plan.Readonly.ValueBool()
 	}
@@ -318,7 +380,7 @@ func (r *smbShareResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	populateSMBShareState(ctx, &plan, &result, &resp.Diagnostics)
+	populateSMBShareState(ctx, &plan, &result, r.provider.defaultPool, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -329,6 +391,15 @@ func (r *smbShareResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := r.client.Call(ctx, "sharing.smb.delete", []any{state.ID.ValueInt64()}, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error Deleting SMB Share", err.Error())
@@ -337,28 +408,45 @@ func (r *smbShareResource) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (r *smbShareResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
+	id, err := resolveImportID(ctx, r.client, req.ID, lookupSMBShare)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Importing SMB Share",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
+			fmt.Sprintf("Could not resolve import ID %q: %s", req.ID, err.Error()),
 		)
 		return
 	}
 
+	// The ownership check reads the object, which is skipped before the provider is
+	// configured.
+	if r.client != nil {
+		var result smbShareResult
+		if err := r.client.Call(ctx, "sharing.smb.get_instance", []any{id}, &result); err == nil {
+			_, tag := splitOwnershipMarker(result.Comment)
+			checkImportOwnership(r.provider, "SMB Share", result.Name, tag, &resp.Diagnostics)
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
-func populateSMBShareState(ctx context.Context, model *smbShareResourceModel, result *smbShareResult, diags *diag.Diagnostics) {
+func populateSMBShareState(ctx context.Context, model *smbShareResourceModel, result *smbShareResult, defaultPool string, diags *diag.Diagnostics) {
 	model.ID = types.Int64Value(result.ID)
 	model.Name = types.StringValue(result.Name)
-	model.Path = types.StringValue(result.Path)
+	model.Path = keepRelativePath(defaultPool, mountPath, model.Path, result.Path)
+	model.ResolvedPath = types.StringValue(result.Path)
 
-	if result.Comment != "" {
-		model.Comment = types.StringValue(result.Comment)
+	comment, tag := splitOwnershipMarker(result.Comment)
+	if comment != "" {
+		model.Comment = types.StringValue(comment)
 	} else {
 		model.Comment = types.StringNull()
 	}
+	if tag != "" {
+		model.OwnershipTag = types.StringValue(tag)
+	} else {
+		model.OwnershipTag = types.StringNull()
+	}
 
 	model.Enabled = types.BoolValue(result.Enabled)
 	model.Purpose = types.StringValue(result.Purpose)
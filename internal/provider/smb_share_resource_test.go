@@ -28,6 +28,12 @@ func TestAccSMBShareResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "truenas_smb_share.test",
+				ImportState:       true,
+				ImportStateId:     "tf-acc-test-smb",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -59,7 +65,8 @@ func TestAccSMBShareResource_update(t *testing.T) {
 func testAccSMBShareResourceConfig(dsName string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test_smb" {
-  name = %q
+  name                = %q
+  deletion_protection = false
 }
 
 resource "truenas_smb_share" "test" {
@@ -72,7 +79,8 @@ resource "truenas_smb_share" "test" {
 func testAccSMBShareResourceConfigWithComment(dsName string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test_smb" {
-  name = %q
+  name                = %q
+  deletion_protection = false
 }
 
 resource "truenas_smb_share" "test" {
@@ -86,7 +94,8 @@ resource "truenas_smb_share" "test" {
 func testAccSMBShareResourceConfigUpdated(dsName string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "truenas_pool_dataset" "test_smb" {
-  name = %q
+  name                = %q
+  deletion_protection = false
 }
 
 resource "truenas_smb_share" "test" {
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// stateUpgradeStep rewrites the raw JSON state of one schema version into the next.
+// Numbers are decoded as json.Number so that large IDs and sizes keep their precision.
+type stateUpgradeStep func(state map[string]any) error
+
+// stateUpgraders builds the UpgradeState map for a resource whose schema has changed
+// len(steps) times: steps[n] upgrades version n to n+1, and the schema's Version must be
+// len(steps). Terraform only calls the upgrader for the version found in state, so each
+// upgrader runs all remaining steps up to the current version.
+//
+// Steps work on the raw JSON state rather than typed prior schemas, so an old schema
+// never needs to be kept around. A step must leave only attributes that exist in the
+// next version: unknown attributes are rejected when the result is decoded.
+func stateUpgraders(steps ...stateUpgradeStep) map[int64]resource.StateUpgrader {
+	upgraders := make(map[int64]resource.StateUpgrader, len(steps))
+	for from := range steps {
+		remaining := steps[from:]
+		upgraders[int64(from)] = resource.StateUpgrader{
+			StateUpgrader: func(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				if req.RawState == nil || req.RawState.JSON == nil {
+					resp.Diagnostics.AddError(
+						"Unable to Upgrade Resource State",
+						fmt.Sprintf("The saved state for schema version %d is not in JSON format.", from),
+					)
+					return
+				}
+
+				upgraded, err := upgradeRawState(req.RawState.JSON, remaining)
+				if err != nil {
+					resp.Diagnostics.AddError(
+						"Unable to Upgrade Resource State",
+						fmt.Sprintf("Could not upgrade the saved state from schema version %d: %s", from, err.Error()),
+					)
+					return
+				}
+				resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+			},
+		}
+	}
+	return upgraders
+}
+
+func upgradeRawState(raw []byte, steps []stateUpgradeStep) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var state map[string]any
+	if err := dec.Decode(&state); err != nil {
+		return nil, fmt.Errorf("invalid state JSON: %w", err)
+	}
+	for _, step := range steps {
+		if err := step(state); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(state)
+}
+
+// numbersToStrings is a stateUpgradeStep for attributes whose type changed from a number
+// to a string, e.g. sizes that now accept units. Null and string values are left alone.
+func numbersToStrings(attrs ...string) stateUpgradeStep {
+	return func(state map[string]any) error {
+		for _, attr := range attrs {
+			if n, ok := state[attr].(json.Number); ok {
+				state[attr] = n.String()
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// testUpgradeState runs the state fixture testdata/state/<name>/v<version>.json through
+// the provider server's UpgradeResourceState, as Terraform does when it finds state
+// written with an older schema version, and returns the upgraded state. name is the
+// resource type name without the "truenas_" prefix.
+func testUpgradeState(t *testing.T, name string, version int64) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", "state", name, fmt.Sprintf("v%d.json", version)))
+	if err != nil {
+		t.Fatalf("unable to read state fixture: %s", err)
+	}
+
+	typeName := "truenas_" + name
+	schemaResp := testResourceSchema(t, typeName)
+
+	server := providerserver.NewProtocol6(New()())()
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: raw},
+	})
+	if err != nil {
+		t.Fatalf("UpgradeResourceState failed: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("UpgradeResourceState returned an error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	value, err := resp.UpgradedState.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %s", err)
+	}
+	return tfsdk.State{Schema: schemaResp.Schema, Raw: value}
+}
+
+// testResourceSchema returns the schema of the provider resource with the given type name.
+func testResourceSchema(t *testing.T, typeName string) resource.SchemaResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	p := New()()
+	for _, newResource := range p.Resources(ctx) {
+		r := newResource()
+		var metaResp resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "truenas"}, &metaResp)
+		if metaResp.TypeName != typeName {
+			continue
+		}
+		var schemaResp resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+		if schemaResp.Diagnostics.HasError() {
+			t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+		}
+		return schemaResp
+	}
+	t.Fatalf("resource %s not found", typeName)
+	return resource.SchemaResponse{}
+}
+
+// testCheckStateAttr fails the test unless the attribute at p equals want.
+func testCheckStateAttr[T attr.Value](t *testing.T, state tfsdk.State, p path.Path, want T) {
+	t.Helper()
+
+	var got T
+	if diags := state.GetAttribute(context.Background(), p, &got); diags.HasError() {
+		t.Fatalf("unable to read %s: %v", p, diags)
+	}
+	if !got.Equal(want) {
+		t.Errorf("%s: expected %s, got %s", p, want, got)
+	}
+}
+
+func TestUpgradeRawState(t *testing.T) {
+	steps := []stateUpgradeStep{
+		numbersToStrings("size"),
+		func(state map[string]any) error {
+			state["name"] = state["old_name"]
+			delete(state, "old_name")
+			return nil
+		},
+	}
+
+	got, err := upgradeRawState([]byte(`{"size": 9007199254740993, "old_name": "a", "other": 1}`), steps)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"name":"a","other":1,"size":"9007199254740993"}`
+	if string(got) != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if _, err := upgradeRawState([]byte(`not json`), steps); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestStateUpgradersRunRemainingSteps(t *testing.T) {
+	var ran []int
+	step := func(n int) stateUpgradeStep {
+		return func(map[string]any) error {
+			ran = append(ran, n)
+			return nil
+		}
+	}
+
+	upgraders := stateUpgraders(step(0), step(1), step(2))
+	if len(upgraders) != 3 {
+		t.Fatalf("expected 3 upgraders, got %d", len(upgraders))
+	}
+
+	var resp resource.UpgradeStateResponse
+	upgraders[1].StateUpgrader(context.Background(), resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: []byte(`{}`)},
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if fmt.Sprint(ran) != "[1 2]" {
+		t.Errorf("expected steps [1 2] to run, got %v", ran)
+	}
+}
+
+func TestPoolDatasetResourceUpgradeStateV0(t *testing.T) {
+	state := testUpgradeState(t, "pool_dataset", 0)
+
+	testCheckStateAttr(t, state, path.Root("quota"), sizeBytes(1099511627776))
+	testCheckStateAttr(t, state, path.Root("refquota"), sizeBytes(0))
+	testCheckStateAttr(t, state, path.Root("reservation"), sizeNull())
+	testCheckStateAttr(t, state, path.Root("refreservation"), sizeBytes(10737418240))
+	testCheckStateAttr(t, state, path.Root("name"), types.StringValue("tank/media"))
+}
+
+func TestISCSIExtentResourceUpgradeStateV0(t *testing.T) {
+	state := testUpgradeState(t, "iscsi_extent", 0)
+
+	testCheckStateAttr(t, state, path.Root("filesize"), sizeBytes(10737418240))
+	testCheckStateAttr(t, state, path.Root("name"), types.StringValue("extent1"))
+}
+
+func TestNVMeTNamespaceResourceUpgradeStateV0(t *testing.T) {
+	state := testUpgradeState(t, "nvmet_namespace", 0)
+
+	testCheckStateAttr(t, state, path.Root("filesize"), sizeBytes(21474836480))
+	testCheckStateAttr(t, state, path.Root("device_path"), types.StringValue("/mnt/tank/nvmet/ns1"))
+}
@@ -0,0 +1,16 @@
+package provider
+
+import "time"
+
+// Default operation deadlines, used when a resource's timeouts attribute does not
+// override them. The deadline is carried by the context passed to client.Call and
+// to any job or state polling performed by the operation.
+const (
+	defaultCreateTimeout = 20 * time.Minute
+	defaultUpdateTimeout = 20 * time.Minute
+	defaultDeleteTimeout = 20 * time.Minute
+
+	// defaultServiceTimeout bounds creating or updating a truenas_service, which
+	// includes waiting for the service to start or stop.
+	defaultServiceTimeout = 30 * time.Second
+)
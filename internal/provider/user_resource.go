@@ -2,13 +2,10 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
@@ -16,38 +13,27 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	"github.com/barodeur/terraform-provider-truenas/internal/client"
-)
-
-var (
-	_ resource.Resource                = (*userResource)(nil)
-	_ resource.ResourceWithConfigure   = (*userResource)(nil)
-	_ resource.ResourceWithImportState = (*userResource)(nil)
 )
 
-type userResource struct {
-	client *client.Client
-}
-
 type userResourceModel struct {
-	ID               types.Int64  `tfsdk:"id"`
-	UID              types.Int64  `tfsdk:"uid"`
-	Username         types.String `tfsdk:"username"`
-	FullName         types.String `tfsdk:"full_name"`
-	Email            types.String `tfsdk:"email"`
-	Password         types.String `tfsdk:"password"`
-	PasswordDisabled types.Bool   `tfsdk:"password_disabled"`
-	Group            types.Int64  `tfsdk:"group"`
-	GroupCreate      types.Bool   `tfsdk:"group_create"`
-	Groups           types.List   `tfsdk:"groups"`
-	Home             types.String `tfsdk:"home"`
-	HomeCreate       types.Bool   `tfsdk:"home_create"`
-	Shell            types.String `tfsdk:"shell"`
-	Sshpubkey        types.String `tfsdk:"sshpubkey"`
-	Smb              types.Bool   `tfsdk:"smb"`
-	Locked           types.Bool   `tfsdk:"locked"`
-	Builtin          types.Bool   `tfsdk:"builtin"`
+	ID               types.Int64    `tfsdk:"id"`
+	UID              types.Int64    `tfsdk:"uid"`
+	Username         types.String   `tfsdk:"username"`
+	FullName         types.String   `tfsdk:"full_name"`
+	Email            types.String   `tfsdk:"email"`
+	Password         types.String   `tfsdk:"password"`
+	PasswordDisabled types.Bool     `tfsdk:"password_disabled"`
+	Group            types.Int64    `tfsdk:"group"`
+	GroupCreate      types.Bool     `tfsdk:"group_create"`
+	Groups           types.List     `tfsdk:"groups"`
+	Home             types.String   `tfsdk:"home"`
+	HomeCreate       types.Bool     `tfsdk:"home_create"`
+	Shell            types.String   `tfsdk:"shell"`
+	Sshpubkey        types.String   `tfsdk:"sshpubkey"`
+	Smb              types.Bool     `tfsdk:"smb"`
+	Locked           types.Bool     `tfsdk:"locked"`
+	Builtin          types.Bool     `tfsdk:"builtin"`
+	Timeouts         timeouts.Value `tfsdk:"timeouts"`
 }
 
 type userGroupRef struct {
@@ -72,15 +58,20 @@ type userResult struct {
 }
 
 func NewUserResource() resource.Resource {
-	return &userResource{}
+	return newCRUDResource(crudSpec[userResourceModel, userResult]{
+		typeName:     "user",
+		namespace:    "user",
+		kind:         "User",
+		schema:       userSchema,
+		params:       userParams,
+		populate:     populateUserState,
+		importLookup: lookupUser,
+		deleteArgs:   userDeleteArgs,
+	})
 }
 
-func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_user"
-}
-
-func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func userSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		MarkdownDescription: "Manages a TrueNAS local user.\n\n" +
 			"~> **Note:** The `password` attribute is write-only and cannot be read back from TrueNAS. " +
 			"After `terraform import`, `password` will be null.",
@@ -192,34 +183,12 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *userResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		return
-	}
-
-	c, ok := req.ProviderData.(*client.Client)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
-		)
-		return
-	}
-
-	r.client = c
-}
-
-func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan userResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
+func userParams(ctx context.Context, plan, state *userResourceModel, diags *diag.Diagnostics) map[string]any {
 	params := map[string]any{
 		"username":  plan.Username.ValueString(),
 		"full_name": plan.FullName.ValueString(),
@@ -228,7 +197,8 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 	if !plan.Email.IsNull() && !plan.Email.IsUnknown() {
 		params["email"] = plan.Email.ValueString()
 	}
-	if !plan.Password.IsNull() && !plan.Password.IsUnknown() {
+	// The password is only sent when it changes.
+	if !plan.Password.IsNull() && !plan.Password.IsUnknown() && (state == nil || !plan.Password.Equal(state.Password)) {
 		params["password"] = plan.Password.ValueString()
 	}
 	if !plan.PasswordDisabled.IsNull() && !plan.PasswordDisabled.IsUnknown() {
@@ -237,31 +207,21 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 	if !plan.Group.IsNull() && !plan.Group.IsUnknown() {
 		params["group"] = plan.Group.ValueInt64()
 	}
-	if !plan.GroupCreate.IsNull() && !plan.GroupCreate.IsUnknown() {
-		params["group_create"] = plan.GroupCreate.ValueBool()
-	} else if plan.Group.IsNull() || plan.Group.IsUnknown() {
-		// Default: create a group if no explicit group is set
-		params["group_create"] = true
-	}
 	if !plan.Groups.IsNull() && !plan.Groups.IsUnknown() {
 		var groupIDs []int64
-		resp.Diagnostics.Append(plan.Groups.ElementsAs(ctx, &groupIDs, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+		diags.Append(plan.Groups.ElementsAs(ctx, &groupIDs, false)...)
 		params["groups"] = groupIDs
 	}
 	if !plan.Home.IsNull() && !plan.Home.IsUnknown() {
 		params["home"] = plan.Home.ValueString()
 	}
-	if !plan.HomeCreate.IsNull() && !plan.HomeCreate.IsUnknown() && plan.HomeCreate.ValueBool() {
-		params["home_create"] = true
-	}
 	if !plan.Shell.IsNull() && !plan.Shell.IsUnknown() {
 		params["shell"] = plan.Shell.ValueString()
 	}
 	if !plan.Sshpubkey.IsNull() && !plan.Sshpubkey.IsUnknown() {
 		params["sshpubkey"] = plan.Sshpubkey.ValueString()
+	} else if plan.Sshpubkey.IsNull() && state != nil {
+		params["sshpubkey"] = ""
 	}
 	if !plan.Smb.IsNull() && !plan.Smb.IsUnknown() {
 		params["smb"] = plan.Smb.ValueBool()
@@ -270,148 +230,33 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 		params["locked"] = plan.Locked.ValueBool()
 	}
 
-	var result userResult
-	err := r.client.Call(ctx, "user.create", []any{params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating User", err.Error())
-		return
-	}
-
-	populateUserState(ctx, &plan, &result, &resp.Diagnostics)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state userResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var result userResult
-	err := r.client.Call(ctx, "user.get_instance", []any{state.ID.ValueInt64()}, &result)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
-			resp.State.RemoveResource(ctx)
-			return
+	// The primary group and home directory are only created along with the user.
+	if state == nil {
+		if !plan.GroupCreate.IsNull() && !plan.GroupCreate.IsUnknown() {
+			params["group_create"] = plan.GroupCreate.ValueBool()
+		} else if plan.Group.IsNull() || plan.Group.IsUnknown() {
+			// Default: create a group if no explicit group is set
+			params["group_create"] = true
 		}
-		resp.Diagnostics.AddError("Error Reading User", err.Error())
-		return
-	}
-
-	// Preserve password from prior state since it can't be read back
-	priorPassword := state.Password
-
-	populateUserState(ctx, &state, &result, &resp.Diagnostics)
-	state.Password = priorPassword
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-}
-
-func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan userResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	var state userResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	params := map[string]any{
-		"username":  plan.Username.ValueString(),
-		"full_name": plan.FullName.ValueString(),
-	}
-
-	if !plan.Email.IsNull() && !plan.Email.IsUnknown() {
-		params["email"] = plan.Email.ValueString()
-	}
-	if !plan.Password.IsNull() && !plan.Password.IsUnknown() && !plan.Password.Equal(state.Password) {
-		params["password"] = plan.Password.ValueString()
-	}
-	if !plan.PasswordDisabled.IsNull() && !plan.PasswordDisabled.IsUnknown() {
-		params["password_disabled"] = plan.PasswordDisabled.ValueBool()
-	}
-	if !plan.Group.IsNull() && !plan.Group.IsUnknown() {
-		params["group"] = plan.Group.ValueInt64()
-	}
-	if !plan.Groups.IsNull() && !plan.Groups.IsUnknown() {
-		var groupIDs []int64
-		resp.Diagnostics.Append(plan.Groups.ElementsAs(ctx, &groupIDs, false)...)
-		if resp.Diagnostics.HasError() {
-			return
+		if !plan.HomeCreate.IsNull() && !plan.HomeCreate.IsUnknown() && plan.HomeCreate.ValueBool() {
+			params["home_create"] = true
 		}
-		params["groups"] = groupIDs
-	}
-	if !plan.Home.IsNull() && !plan.Home.IsUnknown() {
-		params["home"] = plan.Home.ValueString()
-	}
-	if !plan.Shell.IsNull() && !plan.Shell.IsUnknown() {
-		params["shell"] = plan.Shell.ValueString()
-	}
-	if plan.Sshpubkey.IsNull() {
-		params["sshpubkey"] = ""
-	} else if !plan.Sshpubkey.IsUnknown() {
-		params["sshpubkey"] = plan.Sshpubkey.ValueString()
-	}
-	if !plan.Smb.IsNull() && !plan.Smb.IsUnknown() {
-		params["smb"] = plan.Smb.ValueBool()
-	}
-	if !plan.Locked.IsNull() && !plan.Locked.IsUnknown() {
-		params["locked"] = plan.Locked.ValueBool()
-	}
-
-	var result userResult
-	err := r.client.Call(ctx, "user.update", []any{state.ID.ValueInt64(), params}, &result)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Updating User", err.Error())
-		return
 	}
 
-	// Preserve password from plan since it can't be read back
-	priorPassword := plan.Password
-
-	populateUserState(ctx, &plan, &result, &resp.Diagnostics)
-	plan.Password = priorPassword
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
-
-func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state userResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Only delete the primary group if the provider created it (group_create was explicitly true).
-	// When the user provided an existing group ID, we must not delete it.
-	deleteGroup := !state.GroupCreate.IsNull() && state.GroupCreate.ValueBool()
-	deleteOpts := map[string]any{
-		"delete_group": deleteGroup,
-	}
-
-	err := r.client.Call(ctx, "user.delete", []any{state.ID.ValueInt64(), deleteOpts}, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting User", err.Error())
-		return
-	}
+	return params
 }
 
-func (r *userResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Importing User",
-			fmt.Sprintf("Could not parse ID %q as an integer: %s", req.ID, err.Error()),
-		)
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+// userDeleteArgs only deletes the primary group if the provider created it
+// (group_create was explicitly true). When the user provided an existing group ID, it
+// must not be deleted.
+func userDeleteArgs(state *userResourceModel) []any {
+	return []any{map[string]any{
+		"delete_group": !state.GroupCreate.IsNull() && state.GroupCreate.ValueBool(),
+	}}
 }
 
+// populateUserState copies a user into the model. The password cannot be read back and
+// is left as it is.
 func populateUserState(ctx context.Context, model *userResourceModel, result *userResult, diags *diag.Diagnostics) {
 	model.ID = types.Int64Value(result.ID)
 	model.UID = types.Int64Value(result.UID)
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = (*validateCronFunction)(nil)
+
+type validateCronFunction struct{}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string // optional symbolic names, index 0 maps to min
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "dom", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "dow", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat", "sun"}},
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func NewValidateCronFunction() function.Function {
+	return &validateCronFunction{}
+}
+
+func (f *validateCronFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_cron"
+}
+
+func (f *validateCronFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validate a cron expression and split it into a TrueNAS schedule.",
+		MarkdownDescription: "Validates a five-field cron expression (or a macro such as `@daily`) and returns an object with " +
+			"`minute`, `hour`, `dom`, `month` and `dow` attributes, matching the `schedule` attribute of " +
+			"`truenas_cronjob` and `truenas_pool_snapshot_task`. Invalid expressions fail with an error naming the offending field. " +
+			"The object has no `begin` or `end` attributes: assigned to a `truenas_pool_snapshot_task` schedule, they keep their " +
+			"defaults, or can be added with `merge(provider::truenas::validate_cron(\"0 * * * *\"), { begin = \"08:00\", end = \"18:00\" })`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "schedule",
+				Description: "The cron expression, e.g. \"*/15 2-4 * * mon-fri\".",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: cronjobScheduleAttrTypes,
+		},
+	}
+}
+
+func (f *validateCronFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var schedule string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &schedule))
+	if resp.Error != nil {
+		return
+	}
+
+	fields, err := parseCronSchedule(schedule)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result := cronjobScheduleModel{
+		Minute: types.StringValue(fields[0]),
+		Hour:   types.StringValue(fields[1]),
+		Dom:    types.StringValue(fields[2]),
+		Month:  types.StringValue(fields[3]),
+		Dow:    types.StringValue(fields[4]),
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// parseCronSchedule validates a cron expression and returns its five fields.
+func parseCronSchedule(schedule string) ([]string, error) {
+	expr := strings.TrimSpace(schedule)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected %d fields, got %d", schedule, len(cronFields), len(fields))
+	}
+
+	for i, field := range cronFields {
+		if err := field.validate(fields[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s", schedule, err)
+		}
+	}
+
+	return fields, nil
+}
+
+func (f cronField) validate(spec string) error {
+	for _, item := range strings.Split(spec, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			step, err := strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return fmt.Errorf("%s: invalid step %q", f.name, stepPart)
+			}
+		}
+
+		if rangePart == "*" {
+			continue
+		}
+
+		low, high, isRange := strings.Cut(rangePart, "-")
+		lowValue, err := f.value(low)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		highValue, err := f.value(high)
+		if err != nil {
+			return err
+		}
+		if lowValue > highValue {
+			return fmt.Errorf("%s: range %q is reversed", f.name, rangePart)
+		}
+	}
+	return nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %q is not a number", f.name, s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s: %d is out of range %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
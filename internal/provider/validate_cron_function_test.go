@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestValidateCronFunction(t *testing.T) {
+	call := func(schedule string) string {
+		return `jsonencode(provider::truenas::validate_cron("` + schedule + `"))`
+	}
+	schedule := func(minute, hour, dom, month, dow string) string {
+		return `{"dom":"` + dom + `","dow":"` + dow + `","hour":"` + hour + `","minute":"` + minute + `","month":"` + month + `"}`
+	}
+
+	testFunction(t, map[string]functionTestCase{
+		"every minute":    {expr: call("* * * * *"), want: schedule("*", "*", "*", "*", "*")},
+		"steps and lists": {expr: call("*/15 2,4 1-15/2 * 1-5"), want: schedule("*/15", "2,4", "1-15/2", "*", "1-5")},
+		"names":           {expr: call("0 3 * jan-jun mon-fri"), want: schedule("0", "3", "*", "jan-jun", "mon-fri")},
+		"sunday as 7":     {expr: call("0 0 * * 7"), want: schedule("0", "0", "*", "*", "7")},
+		"macro":           {expr: call("@daily"), want: schedule("0", "0", "*", "*", "*")},
+		"attribute":       {expr: `provider::truenas::validate_cron("30 4 * * sun").dow`, want: "sun"},
+		"too few fields":  {expr: call("0 3 * *"), wantErr: `expected 5 fields`},
+		"minute range":    {expr: call("60 * * * *"), wantErr: `out of range 0-59`},
+		"dom zero":        {expr: call("0 0 0 * *"), wantErr: `out of range 1-31`},
+		"reversed range":  {expr: call("0 5-2 * * *"), wantErr: `is reversed`},
+		"zero step":       {expr: call("*/0 * * * *"), wantErr: `invalid step`},
+		"garbage":         {expr: call("0 noon * * *"), wantErr: `is not a number`},
+	})
+}
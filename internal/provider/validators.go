@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+var (
+	_ validator.String = cronFieldValidator{}
+	_ validator.List   = singleStripeVdevValidator{}
+)
+
+// timeOfDayRegexp matches the HH:MM times used for schedule windows.
+var timeOfDayRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// cronFieldValidator validates a single field of a cron schedule (minute, hour, dom, month or dow).
+type cronFieldValidator struct {
+	field cronField
+}
+
+func validCronField(name string) cronFieldValidator {
+	for _, f := range cronFields {
+		if f.name == name {
+			return cronFieldValidator{field: f}
+		}
+	}
+	panic(fmt.Sprintf("unknown cron field %q", name))
+}
+
+func (v cronFieldValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be a valid cron %s field (%d-%d, *, lists, ranges and steps)", v.field.name, v.field.min, v.field.max)
+}
+
+func (v cronFieldValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cronFieldValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := v.field.validate(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Cron Field", err.Error())
+	}
+}
+
+// singleStripeVdevValidator allows at most one STRIPE vdev in a list of pool vdevs.
+// pool.query reports each striped disk as its own vdev, and they are read back as a
+// single STRIPE vdev, so a second one would never match the state.
+type singleStripeVdevValidator struct{}
+
+func (v singleStripeVdevValidator) Description(_ context.Context) string {
+	return "at most one vdev may have type STRIPE; list all striped disks in it"
+}
+
+func (v singleStripeVdevValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v singleStripeVdevValidator) ValidateList(_ context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	first := -1
+	for i, elem := range req.ConfigValue.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok {
+			continue
+		}
+		typ, ok := obj.Attributes()["type"].(types.String)
+		if !ok || typ.ValueString() != "STRIPE" {
+			continue
+		}
+		if first >= 0 {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i).AtName("type"), "Multiple STRIPE Vdevs",
+				fmt.Sprintf("Vdev %d is already a STRIPE vdev. List all striped disks in that vdev.", first))
+			return
+		}
+		first = i
+	}
+}
+
+// validateServerChoice checks a planned value against the values returned by a
+// TrueNAS choices method (e.g. pool.dataset.compression_choices). Methods may return
+// either a list of values or an object keyed by value. The check is skipped when the
+// value is not yet known, unchanged from state, or the method cannot be queried.
+func validateServerChoice(ctx context.Context, c *client.Client, method string, params any, attrPath path.Path, planned, prior types.String, diags *diag.Diagnostics) {
+	if c == nil || planned.IsNull() || planned.IsUnknown() || planned.Equal(prior) {
+		return
+	}
+
+	var raw json.RawMessage
+	if err := c.Call(ctx, method, params, &raw); err != nil {
+		tflog.Warn(ctx, "Unable to query TrueNAS choices, skipping plan-time validation", map[string]any{
+			"method": method,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	var choices []string
+	if err := json.Unmarshal(raw, &choices); err != nil {
+		var keyed map[string]any
+		if err := json.Unmarshal(raw, &keyed); err != nil {
+			tflog.Warn(ctx, "Unexpected TrueNAS choices format, skipping plan-time validation", map[string]any{"method": method})
+			return
+		}
+		for k := range keyed {
+			choices = append(choices, k)
+		}
+	}
+
+	if slices.Contains(choices, planned.ValueString()) {
+		return
+	}
+
+	slices.Sort(choices)
+	diags.AddAttributeError(
+		attrPath,
+		"Invalid Attribute Value",
+		fmt.Sprintf("%q is not supported by this TrueNAS system. Allowed values (from %s): %s.",
+			planned.ValueString(), method, strings.Join(choices, ", ")),
+	)
+}
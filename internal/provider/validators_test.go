@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCronFieldValidator(t *testing.T) {
+	tests := []struct {
+		field   string
+		value   types.String
+		wantErr bool
+	}{
+		{field: "minute", value: types.StringValue("*/15")},
+		{field: "minute", value: types.StringValue("00")},
+		{field: "hour", value: types.StringValue("2-4,22")},
+		{field: "month", value: types.StringValue("jan-jun")},
+		{field: "dow", value: types.StringValue("mon-fri")},
+		{field: "minute", value: types.StringNull()},
+		{field: "minute", value: types.StringUnknown()},
+		{field: "minute", value: types.StringValue("60"), wantErr: true},
+		{field: "hour", value: types.StringValue("4-2"), wantErr: true},
+		{field: "dom", value: types.StringValue("0"), wantErr: true},
+		{field: "dow", value: types.StringValue("*/0"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+"/"+tt.value.String(), func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("schedule").AtName(tt.field),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+			validCronField(tt.field).ValidateString(context.Background(), req, resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSingleStripeVdevValidator(t *testing.T) {
+	vdev := func(typ string) attr.Value {
+		return types.ObjectValueMust(poolVdevAttrTypes, map[string]attr.Value{
+			"type":  types.StringValue(typ),
+			"disks": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("S1")}),
+		})
+	}
+	vdevs := func(typs ...string) types.List {
+		elems := make([]attr.Value, len(typs))
+		for i, typ := range typs {
+			elems[i] = vdev(typ)
+		}
+		return types.ListValueMust(types.ObjectType{AttrTypes: poolVdevAttrTypes}, elems)
+	}
+
+	tests := []struct {
+		name    string
+		value   types.List
+		wantErr bool
+	}{
+		{name: "null", value: types.ListNull(types.ObjectType{AttrTypes: poolVdevAttrTypes})},
+		{name: "one stripe", value: vdevs("STRIPE", "MIRROR")},
+		{name: "mirrors", value: vdevs("MIRROR", "MIRROR")},
+		{name: "two stripes", value: vdevs("STRIPE", "MIRROR", "STRIPE"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.ListRequest{
+				Path:        path.Root("topology").AtName("cache"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.ListResponse{}
+			singleStripeVdevValidator{}.ValidateList(context.Background(), req, resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
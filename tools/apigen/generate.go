@@ -0,0 +1,509 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// field is one attribute of the generated resource.
+type field struct {
+	Name     string // API and Terraform attribute name
+	GoName   string
+	Kind     scalarKind
+	Nullable bool
+	Doc      string
+	Enum     []any
+	Default  json.RawMessage
+	Input    bool // accepted by <namespace>.create
+	Required bool // required by <namespace>.create
+	InResult bool // returned by <namespace>.get_instance
+}
+
+// skipped is an API field apigen cannot map to a Terraform attribute.
+type skipped struct {
+	Name   string
+	Reason string
+}
+
+// spec is everything needed to render a resource.
+type spec struct {
+	Version   string
+	Namespace string
+	Resource  string // Terraform resource name without the provider prefix
+	Doc       string
+	Fields    []*field
+	Skipped   []skipped
+}
+
+// initialisms are words written in upper case in Go identifiers and diagnostics.
+var initialisms = map[string]string{
+	"acl": "ACL", "api": "API", "dh": "DH", "dhchap": "DHCHAP", "gid": "GID", "ha": "HA",
+	"id": "ID", "ip": "IP", "iscsi": "ISCSI", "naa": "NAA", "nfs": "NFS", "nqn": "NQN",
+	"nvmet": "NVMeT", "ro": "RO", "rpm": "RPM", "smb": "SMB", "tcp": "TCP", "tpc": "TPC",
+	"uid": "UID", "url": "URL", "uuid": "UUID",
+}
+
+// kindNames are the object names used in diagnostics where they differ from initialisms.
+var kindNames = map[string]string{
+	"iscsi": "iSCSI",
+	"nvmet": "NVMe-oF",
+}
+
+func camel(name string, lowerFirst bool) string {
+	var b strings.Builder
+	for i, word := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '.' || r == '-' }) {
+		switch {
+		case i == 0 && lowerFirst:
+			b.WriteString(strings.ToLower(word))
+		case initialisms[word] != "":
+			b.WriteString(initialisms[word])
+		default:
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+func kindName(resource string) string {
+	words := strings.Split(resource, "_")
+	for i, word := range words {
+		switch {
+		case kindNames[word] != "":
+			words[i] = kindNames[word]
+		case initialisms[word] != "":
+			words[i] = initialisms[word]
+		default:
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// buildSpec collects the fields of a namespace from its create and get_instance methods.
+func buildSpec(d *dump, namespace, resource string) (*spec, error) {
+	create, ok := d.Methods[namespace+".create"]
+	if !ok {
+		return nil, fmt.Errorf("method %s.create not found in the schema dump", namespace)
+	}
+	if len(create.Accepts) == 0 {
+		return nil, fmt.Errorf("method %s.create accepts no parameters", namespace)
+	}
+
+	var result *jsonSchema
+	if get, ok := d.Methods[namespace+".get_instance"]; ok && len(get.Returns) > 0 {
+		result = get.Returns[0]
+	} else if len(create.Returns) > 0 {
+		result = create.Returns[0]
+	} else {
+		return nil, fmt.Errorf("neither %s.get_instance nor %s.create declare a result", namespace, namespace)
+	}
+
+	s := &spec{
+		Version:   d.Version,
+		Namespace: namespace,
+		Resource:  resource,
+		Doc:       strings.Join(strings.Fields(create.Description), " "),
+	}
+	fields := map[string]*field{}
+	get := func(name string) *field {
+		f, ok := fields[name]
+		if !ok {
+			f = &field{Name: name, GoName: camel(name, false)}
+			fields[name] = f
+		}
+		return f
+	}
+	unsupported := map[string]string{}
+
+	addProperties := func(root *jsonSchema, input bool) error {
+		r := newResolver(root)
+		obj, err := r.resolve(root)
+		if err != nil {
+			return err
+		}
+		for name, prop := range obj.Properties {
+			kind, nullable, resolved, err := r.classify(prop)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", name, err)
+			}
+			if kind == kindUnsupported {
+				unsupported[name] = describeType(resolved)
+				continue
+			}
+
+			f := get(name)
+			f.Kind = kind
+			f.Nullable = f.Nullable || nullable
+			if f.Doc == "" {
+				f.Doc = doc(resolved)
+			}
+			if len(resolved.Enum) > 0 && f.Enum == nil {
+				f.Enum = resolved.Enum
+			}
+			if input {
+				f.Input = true
+				f.Required = slices.Contains(obj.Required, name)
+				f.Default = resolved.Default
+			} else {
+				f.InResult = true
+			}
+		}
+		return nil
+	}
+
+	if err := addProperties(create.Accepts[0], true); err != nil {
+		return nil, fmt.Errorf("%s.create: %w", namespace, err)
+	}
+	if err := addProperties(result, false); err != nil {
+		return nil, fmt.Errorf("%s result: %w", namespace, err)
+	}
+
+	id, ok := fields["id"]
+	if !ok || id.Kind != kindInt64 {
+		return nil, fmt.Errorf("%s objects have no integer id; the CRUD core cannot manage them", namespace)
+	}
+
+	for _, f := range fields {
+		s.Fields = append(s.Fields, f)
+	}
+	sort.Slice(s.Fields, func(i, j int) bool {
+		if (s.Fields[i].Name == "id") != (s.Fields[j].Name == "id") {
+			return s.Fields[i].Name == "id"
+		}
+		return s.Fields[i].Name < s.Fields[j].Name
+	})
+
+	for name, reason := range unsupported {
+		if _, ok := fields[name]; !ok {
+			s.Skipped = append(s.Skipped, skipped{Name: name, Reason: reason})
+		}
+	}
+	sort.Slice(s.Skipped, func(i, j int) bool { return s.Skipped[i].Name < s.Skipped[j].Name })
+
+	return s, nil
+}
+
+func describeType(s *jsonSchema) string {
+	switch {
+	case s == nil:
+		return "unknown type"
+	case len(s.Type) > 0:
+		return strings.Join(s.Type, " or ")
+	case len(s.AnyOf) > 0 || len(s.OneOf) > 0:
+		return "union"
+	default:
+		return "untyped"
+	}
+}
+
+// goType returns the API struct field type.
+func (f *field) goType() string {
+	var t string
+	switch f.Kind {
+	case kindString:
+		t = "string"
+	case kindInt64:
+		t = "int64"
+	case kindFloat64:
+		t = "float64"
+	case kindBool:
+		t = "bool"
+	case kindStringList:
+		return "[]string"
+	case kindInt64List:
+		return "[]int64"
+	}
+	if f.Nullable {
+		return "*" + t
+	}
+	return t
+}
+
+// tfType returns the suffix shared by the framework type names: types.<X>,
+// schema.<X>Attribute, <x>default and so on.
+func (f *field) tfType() string {
+	switch f.Kind {
+	case kindString:
+		return "String"
+	case kindInt64:
+		return "Int64"
+	case kindFloat64:
+		return "Float64"
+	case kindBool:
+		return "Bool"
+	default:
+		return "List"
+	}
+}
+
+func (f *field) elemType() string {
+	if f.Kind == kindInt64List {
+		return "types.Int64Type"
+	}
+	return "types.StringType"
+}
+
+func (f *field) isList() bool {
+	return f.Kind == kindStringList || f.Kind == kindInt64List
+}
+
+// generator renders a spec and records the packages the output uses.
+type generator struct {
+	s       *spec
+	imports map[string]bool
+}
+
+func (g *generator) use(pkg string) string {
+	g.imports[pkg] = true
+	return pkg[strings.LastIndex(pkg, "/")+1:]
+}
+
+// generate renders the resource source file for s.
+func generate(s *spec) ([]byte, error) {
+	g := &generator{s: s, imports: map[string]bool{}}
+	body := g.body()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by apigen from the TrueNAS %s API schema (%s). Review and\n", s.Version, s.Namespace)
+	fmt.Fprintf(&buf, "// edit before use: see tools/apigen/README.md.\n\n")
+	buf.WriteString("package provider\n\nimport (\n")
+	var pkgs []string
+	for pkg := range g.imports {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		if !strings.Contains(pkg, ".") {
+			fmt.Fprintf(&buf, "\t%q\n", pkg)
+		}
+	}
+	buf.WriteString("\n")
+	for _, pkg := range pkgs {
+		if strings.Contains(pkg, ".") {
+			fmt.Fprintf(&buf, "\t%q\n", pkg)
+		}
+	}
+	buf.WriteString(")\n\n")
+	buf.WriteString(body)
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("generated code does not parse: %w", err)
+	}
+	return out, nil
+}
+
+func (g *generator) body() string {
+	s := g.s
+	prefix := camel(s.Resource, true)
+	exported := camel(s.Resource, false)
+	model := prefix + "ResourceModel"
+	result := prefix + "Result"
+
+	types := g.use("github.com/hashicorp/terraform-plugin-framework/types")
+	timeouts := g.use("github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts")
+	g.use("context")
+	g.use("github.com/hashicorp/terraform-plugin-framework/diag")
+	g.use("github.com/hashicorp/terraform-plugin-framework/resource")
+	g.use("github.com/hashicorp/terraform-plugin-framework/resource/schema")
+
+	var b strings.Builder
+	w := func(format string, args ...any) { fmt.Fprintf(&b, format, args...) }
+
+	// Model
+	w("type %s struct {\n", model)
+	for _, f := range s.Fields {
+		w("\t%s %s.%s `tfsdk:%q`\n", f.GoName, types, f.tfType(), f.Name)
+	}
+	w("\tTimeouts %s.Value `tfsdk:\"timeouts\"`\n}\n\n", timeouts)
+
+	// API result
+	w("type %s struct {\n", result)
+	for _, f := range s.Fields {
+		if f.InResult {
+			w("\t%s %s `json:%q`\n", f.GoName, f.goType(), f.Name)
+		}
+	}
+	w("}\n\n")
+
+	// Constructor
+	w("func New%sResource() resource.Resource {\n", exported)
+	w("\treturn newCRUDResource(crudSpec[%s, %s]{\n", model, result)
+	w("\t\ttypeName: %q,\n\t\tnamespace: %q,\n\t\tkind: %q,\n", s.Resource, s.Namespace, kindName(s.Resource))
+	w("\t\tschema: %sSchema,\n\t\tparams: %sParams,\n\t\tpopulate: populate%sState,\n", prefix, prefix, exported)
+	w("\t})\n}\n\n")
+
+	// Schema
+	w("func %sSchema(ctx context.Context) schema.Schema {\n", prefix)
+	if len(s.Skipped) > 0 {
+		w("\t// Not generated, as apigen does not support their types:\n")
+		for _, sk := range s.Skipped {
+			w("\t//   - %s (%s)\n", sk.Name, sk.Reason)
+		}
+	}
+	w("\treturn schema.Schema{\n")
+	description := s.Doc
+	if description == "" {
+		description = "Manages a TrueNAS " + kindName(s.Resource) + "."
+	}
+	w("\t\tDescription: %q,\n", description)
+	w("\t\tAttributes: map[string]schema.Attribute{\n")
+	for _, f := range s.Fields {
+		g.attribute(&b, f)
+	}
+	w("\t\t\t\"timeouts\": %s.Attributes(ctx, %s.Opts{Create: true, Update: true, Delete: true}),\n", timeouts, timeouts)
+	w("\t\t},\n\t}\n}\n\n")
+
+	// Params
+	w("func %sParams(ctx context.Context, plan, state *%s, diags *diag.Diagnostics) map[string]any {\n", prefix, model)
+	w("\tparams := map[string]any{}\n\n")
+	for _, f := range s.Fields {
+		if !f.Input || f.Name == "id" {
+			continue
+		}
+		w("\tif !plan.%s.IsNull() && !plan.%s.IsUnknown() {\n", f.GoName, f.GoName)
+		if f.isList() {
+			elem := strings.TrimPrefix(f.goType(), "[]")
+			w("\t\tvar v []%s\n\t\tdiags.Append(plan.%s.ElementsAs(ctx, &v, false)...)\n\t\tparams[%q] = v\n", elem, f.GoName, f.Name)
+		} else {
+			w("\t\tparams[%q] = plan.%s.Value%s()\n", f.Name, f.GoName, f.tfType())
+		}
+		if f.Nullable {
+			w("\t} else if plan.%s.IsNull() && state != nil {\n\t\tparams[%q] = nil\n", f.GoName, f.Name)
+		}
+		w("\t}\n")
+	}
+	w("\n\treturn params\n}\n\n")
+
+	// Populate
+	w("func populate%sState(ctx context.Context, model *%s, result *%s, diags *diag.Diagnostics) {\n", exported, model, result)
+	for _, f := range s.Fields {
+		if !f.InResult {
+			continue
+		}
+		switch {
+		case f.isList():
+			v := camel(f.Name, true) + "List"
+			w("\t%s, d := types.ListValueFrom(ctx, %s, result.%s)\n\tdiags.Append(d...)\n\tmodel.%s = %s\n", v, f.elemType(), f.GoName, f.GoName, v)
+		case f.Nullable:
+			w("\tmodel.%s = types.%sPointerValue(result.%s)\n", f.GoName, f.tfType(), f.GoName)
+		default:
+			w("\tmodel.%s = types.%sValue(result.%s)\n", f.GoName, f.tfType(), f.GoName)
+		}
+	}
+	w("}\n")
+
+	return b.String()
+}
+
+// attribute writes the schema attribute for f.
+func (g *generator) attribute(b *strings.Builder, f *field) {
+	w := func(format string, args ...any) { fmt.Fprintf(b, format, args...) }
+	tf := f.tfType()
+
+	w("\t\t\t%q: schema.%sAttribute{\n", f.Name, tf)
+	if f.Doc != "" {
+		w("\t\t\t\tDescription: %q,\n", f.Doc)
+	}
+	if f.isList() {
+		w("\t\t\t\tElementType: %s,\n", f.elemType())
+	}
+
+	defaultExpr := g.defaultExpr(f)
+	switch {
+	case f.Name == "id" || !f.Input:
+		w("\t\t\t\tComputed: true,\n")
+	case f.Required:
+		w("\t\t\t\tRequired: true,\n")
+	case defaultExpr != "":
+		w("\t\t\t\tOptional: true,\n\t\t\t\tComputed: true,\n\t\t\t\tDefault: %s,\n", defaultExpr)
+	default:
+		w("\t\t\t\tOptional: true,\n")
+	}
+
+	if (f.Name == "id" || !f.Input) && !f.isList() {
+		g.use("github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier")
+		pm := g.use("github.com/hashicorp/terraform-plugin-framework/resource/schema/" + strings.ToLower(tf) + "planmodifier")
+		w("\t\t\t\tPlanModifiers: []planmodifier.%s{\n\t\t\t\t\t%s.UseStateForUnknown(),\n\t\t\t\t},\n", tf, pm)
+	}
+
+	if f.Input && len(f.Enum) > 0 && (f.Kind == kindString || f.Kind == kindInt64) {
+		var values []string
+		for _, v := range f.Enum {
+			if v == nil {
+				continue
+			}
+			if f.Kind == kindString {
+				values = append(values, strconv.Quote(fmt.Sprint(v)))
+			} else {
+				values = append(values, fmt.Sprint(v))
+			}
+		}
+		g.use("github.com/hashicorp/terraform-plugin-framework/schema/validator")
+		v := g.use("github.com/hashicorp/terraform-plugin-framework-validators/" + strings.ToLower(tf) + "validator")
+		w("\t\t\t\tValidators: []validator.%s{\n\t\t\t\t\t%s.OneOf(%s),\n\t\t\t\t},\n", tf, v, strings.Join(values, ", "))
+	}
+	w("\t\t\t},\n")
+}
+
+// defaultExpr returns the schema default for f, or "" if it has none that can be
+// expressed statically.
+func (g *generator) defaultExpr(f *field) string {
+	if len(f.Default) == 0 || string(f.Default) == "null" {
+		return ""
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(f.Default))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return ""
+	}
+
+	pkgFor := func(kind string) string {
+		return g.use("github.com/hashicorp/terraform-plugin-framework/resource/schema/" + kind + "default")
+	}
+
+	switch f.Kind {
+	case kindString:
+		if s, ok := v.(string); ok {
+			return fmt.Sprintf("%s.StaticString(%q)", pkgFor("string"), s)
+		}
+	case kindInt64:
+		if n, ok := v.(json.Number); ok {
+			if _, err := n.Int64(); err == nil {
+				return fmt.Sprintf("%s.StaticInt64(%s)", pkgFor("int64"), n)
+			}
+		}
+	case kindFloat64:
+		if n, ok := v.(json.Number); ok {
+			return fmt.Sprintf("%s.StaticFloat64(%s)", pkgFor("float64"), n)
+		}
+	case kindBool:
+		if bv, ok := v.(bool); ok {
+			return fmt.Sprintf("%s.StaticBool(%t)", pkgFor("bool"), bv)
+		}
+	case kindStringList, kindInt64List:
+		items, ok := v.([]any)
+		if !ok {
+			return ""
+		}
+		g.use("github.com/hashicorp/terraform-plugin-framework/attr")
+		var elems []string
+		for _, item := range items {
+			if f.Kind == kindStringList {
+				elems = append(elems, fmt.Sprintf("types.StringValue(%q)", fmt.Sprint(item)))
+			} else {
+				elems = append(elems, fmt.Sprintf("types.Int64Value(%s)", fmt.Sprint(item)))
+			}
+		}
+		return fmt.Sprintf("%s.StaticValue(types.ListValueMust(%s, []attr.Value{%s}))",
+			pkgFor("list"), f.elemType(), strings.Join(elems, ", "))
+	}
+	return ""
+}
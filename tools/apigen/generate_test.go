@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestGenerateGolden(t *testing.T) {
+	d, err := loadDump(filepath.Join("testdata", "schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := buildSpec(d, "sharing.smb", "smb_share")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "smb_share.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generated code differs from %s; run go test ./tools/apigen -update and review the diff", golden)
+	}
+}
+
+func TestBuildSpecFields(t *testing.T) {
+	d, err := loadDump(filepath.Join("testdata", "schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := buildSpec(d, "sharing.smb", "smb_share")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]*field{}
+	for _, f := range s.Fields {
+		fields[f.Name] = f
+	}
+
+	if f := fields["path"]; f == nil || !f.Required || !f.Input || !f.InResult {
+		t.Errorf("path: expected a required input returned in the result, got %+v", f)
+	}
+	if f := fields["path_suffix"]; f == nil || !f.Nullable {
+		t.Errorf("path_suffix: expected a nullable field, got %+v", f)
+	}
+	if f := fields["purpose"]; f == nil || len(f.Enum) != 3 || string(f.Default) != `"DEFAULT_SHARE"` {
+		t.Errorf("purpose: expected the enum and default of the referenced definition, got %+v", f)
+	}
+	if f := fields["locked"]; f == nil || f.Input {
+		t.Errorf("locked: expected a result-only field, got %+v", f)
+	}
+	if len(s.Skipped) != 1 || s.Skipped[0].Name != "audit" {
+		t.Errorf("expected audit to be skipped, got %+v", s.Skipped)
+	}
+}
+
+func TestBuildSpecErrors(t *testing.T) {
+	d, err := loadDump(filepath.Join("testdata", "schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = buildSpec(d, "sharing.nfs", "nfs_share")
+	if err == nil || !strings.Contains(err.Error(), "sharing.nfs.create not found") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCamel(t *testing.T) {
+	tests := map[string]string{
+		"smb_share":         "SMBShare",
+		"nvmet_host_subsys": "NVMeTHostSubsys",
+		"timemachine_quota": "TimemachineQuota",
+		"dhchap_ctrl_key":   "DHCHAPCtrlKey",
+		"iscsi_extent":      "ISCSIExtent",
+	}
+	for in, want := range tests {
+		if got := camel(in, false); got != want {
+			t.Errorf("camel(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := camel("smb_share", true); got != "smbShare" {
+		t.Errorf("camel(smb_share, lower) = %q", got)
+	}
+	if got := kindName("iscsi_targetextent"); got != "iSCSI Targetextent" {
+		t.Errorf("kindName = %q", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Command apigen builds resource skeletons from the TrueNAS API schema.
+//
+// It has two subcommands:
+//
+//	apigen dump -host nas.example.com -out tools/apigen/schemas/25.04.json
+//	apigen generate -schema tools/apigen/schemas/25.04.json -namespace sharing.smb -resource smb_share
+//
+// dump saves the method schemas returned by core.get_methods, together with the server
+// version, so that generation is reproducible without a live system. generate reads a
+// saved dump and writes a resource built on the provider's generic CRUD core, with the
+// API struct, the Terraform schema (descriptions, enums and defaults) and the mapping
+// between them.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/barodeur/terraform-provider-truenas/internal/client"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "dump":
+		runDump(os.Args[2:])
+	case "generate":
+		runGenerate(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: apigen dump|generate [flags]")
+	os.Exit(2)
+}
+
+func runDump(args []string) {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	host := fs.String("host", os.Getenv("TRUENAS_HOST"), "TrueNAS host or WebSocket URL (defaults to TRUENAS_HOST)")
+	insecure := fs.Bool("insecure", false, "Skip TLS certificate verification")
+	out := fs.String("out", "", "File to write the schema dump to (required)")
+	timeout := fs.Duration("timeout", 2*time.Minute, "Timeout for the whole dump")
+	_ = fs.Parse(args)
+
+	apiKey := os.Getenv("TRUENAS_API_KEY")
+	if *host == "" || apiKey == "" || *out == "" {
+		log.Fatal("dump requires -host (or TRUENAS_HOST), -out and TRUENAS_API_KEY")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	c, err := client.NewClient(ctx, *host, apiKey, *insecure)
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer c.Close()
+
+	var methods map[string]json.RawMessage
+	if err := c.Call(ctx, "core.get_methods", nil, &methods); err != nil {
+		log.Fatalf("core.get_methods failed: %v", err)
+	}
+
+	data, err := json.MarshalIndent(map[string]any{
+		"version": c.ServerVersion().Raw,
+		"methods": methods,
+	}, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode dump: %v", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("Failed to write dump: %v", err)
+	}
+	log.Printf("Wrote %d methods from TrueNAS %s to %s", len(methods), c.ServerVersion().Raw, *out)
+}
+
+func runGenerate(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	schemaPath := fs.String("schema", "", "Schema dump written by apigen dump (required)")
+	namespace := fs.String("namespace", "", "API namespace, e.g. sharing.smb (required)")
+	resource := fs.String("resource", "", "Resource name without the provider prefix, e.g. smb_share (required)")
+	out := fs.String("out", "", "Output file (defaults to stdout)")
+	_ = fs.Parse(args)
+
+	if *schemaPath == "" || *namespace == "" || *resource == "" {
+		log.Fatal("generate requires -schema, -namespace and -resource")
+	}
+
+	d, err := loadDump(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := buildSpec(d, *namespace, *resource)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(s)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(src)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		log.Fatalf("Failed to create the output directory: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
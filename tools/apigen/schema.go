@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// dump is the file written by "apigen dump": the server version and the result of
+// core.get_methods.
+type dump struct {
+	Version string                `json:"version"`
+	Methods map[string]*apiMethod `json:"methods"`
+}
+
+// apiMethod is one entry of core.get_methods.
+type apiMethod struct {
+	Description string        `json:"description"`
+	Accepts     []*jsonSchema `json:"accepts"`
+	Returns     []*jsonSchema `json:"returns"`
+	Job         bool          `json:"job"`
+}
+
+// jsonSchema is the subset of JSON Schema used by the TrueNAS method definitions.
+type jsonSchema struct {
+	Type        schemaType             `json:"type"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Enum        []any                  `json:"enum"`
+	Default     json.RawMessage        `json:"default"`
+	Items       *jsonSchema            `json:"items"`
+	AnyOf       []*jsonSchema          `json:"anyOf"`
+	OneOf       []*jsonSchema          `json:"oneOf"`
+	Properties  map[string]*jsonSchema `json:"properties"`
+	Required    []string               `json:"required"`
+	Ref         string                 `json:"$ref"`
+	Defs        map[string]*jsonSchema `json:"$defs"`
+	Definitions map[string]*jsonSchema `json:"definitions"`
+}
+
+// schemaType holds a JSON Schema "type", which is either a string or a list of strings.
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = schemaType{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings: %w", err)
+	}
+	*t = many
+	return nil
+}
+
+func loadDump(path string) (*dump, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var d dump
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("invalid schema dump %s: %w", path, err)
+	}
+	if len(d.Methods) == 0 {
+		return nil, fmt.Errorf("schema dump %s contains no methods", path)
+	}
+	return &d, nil
+}
+
+// resolver resolves "$ref" pointers against the definitions of a root schema.
+type resolver struct {
+	defs map[string]*jsonSchema
+}
+
+func newResolver(root *jsonSchema) *resolver {
+	r := &resolver{defs: map[string]*jsonSchema{}}
+	for name, s := range root.Definitions {
+		r.defs["#/definitions/"+name] = s
+	}
+	for name, s := range root.Defs {
+		r.defs["#/$defs/"+name] = s
+	}
+	return r
+}
+
+func (r *resolver) resolve(s *jsonSchema) (*jsonSchema, error) {
+	for s != nil && s.Ref != "" {
+		target, ok := r.defs[s.Ref]
+		if !ok {
+			return nil, fmt.Errorf("unresolved reference %s", s.Ref)
+		}
+		s = target
+	}
+	return s, nil
+}
+
+// scalarKind is the Terraform type a JSON Schema maps to.
+type scalarKind int
+
+const (
+	kindUnsupported scalarKind = iota
+	kindString
+	kindInt64
+	kindFloat64
+	kindBool
+	kindStringList
+	kindInt64List
+)
+
+// classify returns the kind of s and whether null is allowed. Unions of null and one
+// other type, written either as a type list or with anyOf/oneOf, are nullable.
+func (r *resolver) classify(s *jsonSchema) (scalarKind, bool, *jsonSchema, error) {
+	resolved, err := r.resolve(s)
+	if err != nil {
+		return kindUnsupported, false, nil, err
+	}
+	if resolved != s {
+		// A property referencing a definition can still carry its own description and default.
+		resolved = mergeDoc(resolved, s)
+	}
+	s = resolved
+
+	nullable := false
+	var types []string
+	for _, t := range s.Type {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		types = append(types, t)
+	}
+
+	if len(s.Type) == 0 {
+		variants := s.AnyOf
+		if len(variants) == 0 {
+			variants = s.OneOf
+		}
+		var rest []*jsonSchema
+		for _, v := range variants {
+			v, err := r.resolve(v)
+			if err != nil {
+				return kindUnsupported, false, nil, err
+			}
+			if len(v.Type) == 1 && v.Type[0] == "null" {
+				nullable = true
+				continue
+			}
+			rest = append(rest, v)
+		}
+		if len(rest) != 1 {
+			return kindUnsupported, nullable, s, nil
+		}
+		kind, innerNullable, inner, err := r.classify(rest[0])
+		return kind, nullable || innerNullable, mergeDoc(inner, s), err
+	}
+
+	if len(types) != 1 {
+		return kindUnsupported, nullable, s, nil
+	}
+
+	switch types[0] {
+	case "string":
+		return kindString, nullable, s, nil
+	case "integer":
+		return kindInt64, nullable, s, nil
+	case "number":
+		return kindFloat64, nullable, s, nil
+	case "boolean":
+		return kindBool, nullable, s, nil
+	case "array":
+		if s.Items == nil {
+			return kindUnsupported, nullable, s, nil
+		}
+		item, itemNullable, _, err := r.classify(s.Items)
+		if err != nil || itemNullable {
+			return kindUnsupported, nullable, s, err
+		}
+		switch item {
+		case kindString:
+			return kindStringList, nullable, s, nil
+		case kindInt64:
+			return kindInt64List, nullable, s, nil
+		}
+	}
+	return kindUnsupported, nullable, s, nil
+}
+
+// mergeDoc returns inner with the description, default and title of outer when inner
+// has none, as these are usually set on the anyOf wrapper or the $ref property.
+func mergeDoc(inner, outer *jsonSchema) *jsonSchema {
+	if inner == nil {
+		return nil
+	}
+	merged := *inner
+	if merged.Description == "" {
+		merged.Description = outer.Description
+	}
+	if merged.Title == "" {
+		merged.Title = outer.Title
+	}
+	if merged.Default == nil {
+		merged.Default = outer.Default
+	}
+	return &merged
+}
+
+// doc returns the description of s as a single line.
+func doc(s *jsonSchema) string {
+	text := s.Description
+	if text == "" {
+		text = s.Title
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
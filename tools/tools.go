@@ -7,4 +7,5 @@ import (
 )
 
 //go:generate tofu fmt -recursive ../examples/
+//go:generate go run ./apigen generate -schema apigen/schemas/25.04.json -namespace sharing.smb -resource smb_share -out ../.apigen/smb_share_resource.go
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate --provider-name truenas --rendered-provider-name truenas --provider-dir ..